@@ -0,0 +1,624 @@
+package bundle
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/hashicorp/hcl/hcl/token"
+	"github.com/kevinswiber/apigee-hcl/dsl"
+	"github.com/kevinswiber/apigee-hcl/dsl/hclerror"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/policy"
+	"github.com/kevinswiber/apigee-hcl/dsl/validate"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// zipModTime is the timestamp given to every zip entry, so the same
+// bundle contents always produce a byte-identical archive.
+var zipModTime = time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// Bundle is an in-memory Apigee proxy or shared flow bundle.
+type Bundle struct {
+	// Name is the name of the API proxy or shared flow.
+	Name string
+
+	// Dir is the top-level directory of the bundle: apiproxy for an
+	// API proxy, sharedflowbundle for a shared flow.
+	Dir string
+
+	// Files maps slash-separated paths, relative to the bundle root
+	// (e.g. apiproxy/proxies/default.xml), to file contents.
+	Files map[string][]byte
+
+	// Warnings holds problems that don't prevent the bundle from
+	// being built, such as unused policies.
+	Warnings []error
+}
+
+// Options controls how HCL sources are decoded.
+type Options struct {
+	// Inputs supply values for the variables declared in the sources.
+	Inputs dsl.Inputs
+
+	// Environment names the environment overlay to apply, if any.
+	Environment string
+
+	// ReadFile loads the local files that policies refer to, such as a
+	// Java callout's jar. A relative path is joined to the directory
+	// of the source's filename before it's passed in. Building fails
+	// if a policy refers to a file and ReadFile is nil, so nothing is
+	// read from the filesystem unless the caller allows it.
+	ReadFile func(filename string) ([]byte, error)
+
+	// ReadDir lists a module's source directory. The module's .hcl
+	// files are then loaded with ReadFile. Decoding fails if the
+	// sources use a module and either is nil.
+	ReadDir func(dirname string) ([]os.FileInfo, error)
+}
+
+// Build compiles HCL sources, keyed by filename, into a Bundle. opts
+// may be nil.
+func Build(sources map[string][]byte, opts *Options) (*Bundle, error) {
+	c, err := Decode(sources, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return Render(c, opts)
+}
+
+// Environments returns the sorted names of the environments declared
+// in HCL sources.
+func Environments(sources map[string][]byte) ([]string, error) {
+	lists, err := parse(sources)
+	if err != nil {
+		return nil, err
+	}
+
+	return dsl.Environments(lists)
+}
+
+// Decode parses HCL sources, keyed by filename, and merges them into
+// a single Config. The environment overlay is applied first, then
+// variable and local references are interpolated. Sources are merged
+// in filename order, and may define only one proxy or shared flow; use
+// Workspace to split sources that define several. Modules are read from
+// their source directories through opts.ReadDir and opts.ReadFile, and
+// merged with their names as a prefix.
+// opts may be nil.
+func Decode(sources map[string][]byte, opts *Options) (*dsl.Config, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	return decode(sources, opts, nil)
+}
+
+// decode is Decode for sources that may belong to a module. parents
+// holds the source directories of the modules being decoded, so a
+// module that includes itself is reported instead of recursing forever.
+func decode(sources map[string][]byte, opts *Options, parents []string) (*dsl.Config, error) {
+	var errors *multierror.Error
+	var c dsl.Config
+
+	lists, err := parse(sources)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := dsl.ApplyEnvironment(lists, opts.Environment); err != nil {
+		return nil, err
+	}
+
+	scope, err := dsl.NewScope(lists, opts.Inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	seenModules := make(map[string]token.Pos)
+	for _, list := range lists {
+		if err := scope.Interpolate(list); err != nil {
+			errors = multierror.Append(errors, err)
+			continue
+		}
+
+		cfg, err := dsl.DecodeConfigHCL(list)
+		if err != nil {
+			errors = multierror.Append(errors, err)
+			continue
+		}
+
+		if err := redefinitionError(&c, cfg); err != nil {
+			errors = multierror.Append(errors, err)
+			continue
+		}
+
+		c.Merge(cfg)
+
+		modules, err := dsl.DecodeModulesHCL(list)
+		if err != nil {
+			errors = multierror.Append(errors, err)
+			continue
+		}
+
+		for _, m := range modules {
+			if first, ok := seenModules[m.Name]; ok {
+				errors = multierror.Append(errors, &hclerror.PosError{
+					Pos: m.Pos,
+					Err: fmt.Errorf("duplicate module %q, first defined at %s, line %d",
+						m.Name, first.Filename, first.Line),
+				})
+				continue
+			}
+			seenModules[m.Name] = m.Pos
+
+			mc, err := decodeModule(m, opts, parents)
+			if err != nil {
+				errors = multierror.Append(errors, err)
+				continue
+			}
+
+			c.Merge(mc)
+		}
+	}
+
+	if errors != nil {
+		return nil, errors
+	}
+
+	return &c, nil
+}
+
+// decodeModule decodes the .hcl files in a module's source directory
+// with the module's inputs, and prefixes the names in the result with
+// the module's name. The files are read through opts.
+func decodeModule(m *dsl.Module, opts *Options, parents []string) (*dsl.Config, error) {
+	if opts.ReadDir == nil || opts.ReadFile == nil {
+		return nil, moduleError(m, fmt.Errorf("can't read %s, no ReadDir and ReadFile are set in the bundle options", m.Source))
+	}
+
+	source, err := filepath.Abs(m.Source)
+	if err != nil {
+		return nil, moduleError(m, err)
+	}
+
+	for _, parent := range parents {
+		if parent == source {
+			return nil, &hclerror.PosError{
+				Pos: m.Pos,
+				Err: fmt.Errorf("module %q includes itself through %s", m.Name, m.Source),
+			}
+		}
+	}
+
+	files, err := opts.ReadDir(m.Source)
+	if err != nil {
+		return nil, moduleError(m, err)
+	}
+
+	sources := make(map[string][]byte)
+	for _, f := range files {
+		if f.IsDir() || filepath.Ext(f.Name()) != ".hcl" {
+			continue
+		}
+
+		file := filepath.Join(m.Source, f.Name())
+		d, err := opts.ReadFile(file)
+		if err != nil {
+			return nil, moduleError(m, err)
+		}
+		sources[file] = d
+	}
+
+	if len(sources) == 0 {
+		return nil, moduleError(m, fmt.Errorf("no .hcl files in %s", m.Source))
+	}
+
+	moduleOpts := &Options{
+		Inputs:      m.Inputs,
+		Environment: opts.Environment,
+		ReadFile:    opts.ReadFile,
+		ReadDir:     opts.ReadDir,
+	}
+
+	c, err := decode(sources, moduleOpts, append(parents, source))
+	if err != nil {
+		return nil, moduleError(m, err)
+	}
+
+	if c.Proxy != nil || c.SharedFlow != nil {
+		return nil, moduleError(m, fmt.Errorf("a module can't define a proxy or shared flow"))
+	}
+
+	c.Namespace(m.Name)
+
+	return c, nil
+}
+
+// moduleError gives errors without a position, such as an unreadable
+// source directory or an undeclared input, the position of the module
+// block.
+func moduleError(m *dsl.Module, err error) error {
+	var errors *multierror.Error
+
+	errs := []error{err}
+	if merr, ok := err.(*multierror.Error); ok {
+		errs = merr.Errors
+	}
+
+	for _, e := range errs {
+		if _, ok := e.(*hclerror.PosError); !ok {
+			e = &hclerror.PosError{
+				Pos: m.Pos,
+				Err: fmt.Errorf("module %q: %v", m.Name, e),
+			}
+		}
+		errors = multierror.Append(errors, e)
+	}
+
+	return errors
+}
+
+// redefinitionError reports a proxy or shared flow in other when c
+// already has one, instead of letting other replace it.
+func redefinitionError(c, other *dsl.Config) error {
+	var errors *multierror.Error
+
+	if c.Proxy != nil && other.Proxy != nil {
+		errors = multierror.Append(errors,
+			conflictError("proxy", other.Proxy.Name, c.Proxy.Name, other.Proxy.Pos, c.Proxy.Pos))
+	}
+
+	if c.SharedFlow != nil && other.SharedFlow != nil {
+		errors = multierror.Append(errors,
+			conflictError("shared flow", other.SharedFlow.Name, c.SharedFlow.Name, other.SharedFlow.Pos, c.SharedFlow.Pos))
+	}
+
+	if errors != nil {
+		return errors
+	}
+
+	return nil
+}
+
+func conflictError(kind, name, firstName string, pos, first token.Pos) error {
+	err := fmt.Errorf("duplicate %s %q, first defined at %s, line %d",
+		kind, name, first.Filename, first.Line)
+	if name != firstName {
+		err = fmt.Errorf("%s %q can't be built along with %s %q, defined at %s, line %d",
+			kind, name, kind, firstName, first.Filename, first.Line)
+	}
+
+	return &hclerror.PosError{Pos: pos, Err: err}
+}
+
+// parse parses HCL sources in filename order.
+func parse(sources map[string][]byte) ([]*ast.ObjectList, error) {
+	var errors *multierror.Error
+
+	var lists []*ast.ObjectList
+	for _, file := range sortedFiles(sources) {
+		list, err := dsl.ParseHCL(file, sources[file])
+		if err != nil {
+			errors = multierror.Append(errors, err)
+			continue
+		}
+		lists = append(lists, list)
+	}
+
+	if errors != nil {
+		return nil, errors
+	}
+
+	return lists, nil
+}
+
+func sortedFiles(sources map[string][]byte) []string {
+	var files []string
+	for file := range sources {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	return files
+}
+
+// Render validates a Config and renders it into a Bundle. A Config with
+// a shared flow is rendered as a shared flow bundle. Local files that
+// policies refer to are loaded with opts.ReadFile; opts may be nil.
+func Render(c *dsl.Config, opts *Options) (*Bundle, error) {
+	var errors *multierror.Error
+
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	if c.SharedFlow != nil {
+		if c.Proxy != nil {
+			errors = multierror.Append(errors,
+				fmt.Errorf("a proxy and a shared flow can't be defined together"))
+		}
+
+		if len(c.ProxyEndpoints) > 0 || len(c.TargetEndpoints) > 0 {
+			errors = multierror.Append(errors,
+				fmt.Errorf("shared flow %q can't define proxy or target endpoints", c.SharedFlow.Name))
+		}
+	} else {
+		if c.Proxy == nil {
+			errors = multierror.Append(errors,
+				fmt.Errorf("no proxy definition found"))
+		}
+
+		if len(c.ProxyEndpoints) == 0 {
+			errors = multierror.Append(errors,
+				fmt.Errorf("no proxy endpoint definition found"))
+		}
+	}
+
+	warnings, err := validate.Config(c)
+	if err != nil {
+		errors = multierror.Append(errors, err)
+	}
+
+	if errors != nil {
+		return nil, errors
+	}
+
+	b := Bundle{
+		Files:    make(map[string][]byte),
+		Warnings: warnings,
+	}
+
+	if c.SharedFlow != nil {
+		b.Name = c.SharedFlow.Name
+		b.Dir = "sharedflowbundle"
+
+		if err := b.addXML(path.Join(b.Dir, b.Name+".xml"), c.SharedFlow); err != nil {
+			errors = multierror.Append(errors, err)
+		}
+
+		p := path.Join(b.Dir, "sharedflows", c.SharedFlow.Flow.Name+".xml")
+		if err := b.addXML(p, c.SharedFlow.Flow); err != nil {
+			errors = multierror.Append(errors, err)
+		}
+	} else {
+		b.Name = c.Proxy.Name
+		b.Dir = "apiproxy"
+
+		if err := b.addXML(path.Join(b.Dir, b.Name+".xml"), c.Proxy); err != nil {
+			errors = multierror.Append(errors, err)
+		}
+	}
+
+	for _, proxyEndpoint := range c.ProxyEndpoints {
+		p := path.Join(b.Dir, "proxies", proxyEndpoint.Name+".xml")
+		if err := b.addXML(p, proxyEndpoint); err != nil {
+			errors = multierror.Append(errors, err)
+		}
+	}
+
+	for _, targetEndpoint := range c.TargetEndpoints {
+		p := path.Join(b.Dir, "targets", targetEndpoint.Name+".xml")
+		if err := b.addXML(p, targetEndpoint); err != nil {
+			errors = multierror.Append(errors, err)
+		}
+	}
+
+	for _, policy := range c.Policies {
+		p := path.Join(b.Dir, "policies", policy.Name()+".xml")
+		if err := b.addXML(p, policy); err != nil {
+			errors = multierror.Append(errors, err)
+		}
+	}
+
+	// Resource URL errors are reported at the policy using the resource.
+	owners := make(map[string]policy.Namer)
+	for _, p := range c.Policies {
+		if resourcer, ok := p.(policy.Resourcer); ok {
+			if url := resourcer.Resource().URL; owners[url] == nil {
+				owners[url] = p
+			}
+		}
+	}
+
+	for url, content := range c.Resources {
+		p, err := b.resourcePath(url)
+		if err != nil {
+			errors = multierror.Append(errors, resourceError(owners[url], err))
+			continue
+		}
+
+		b.Files[p] = content
+	}
+
+	// Resources given as local files, e.g. a Java callout's jar, are
+	// copied as-is so binary content survives.
+	for _, p := range c.Policies {
+		resourcer, ok := p.(policy.Resourcer)
+		if !ok {
+			continue
+		}
+
+		r := resourcer.Resource()
+		if r.Path == "" || r.Content != "" {
+			continue
+		}
+
+		filePath, err := b.resourcePath(r.URL)
+		if err != nil {
+			errors = multierror.Append(errors, resourceError(p, err))
+			continue
+		}
+
+		if opts.ReadFile == nil {
+			errors = multierror.Append(errors,
+				fmt.Errorf("policy %q refers to the file %s, but no ReadFile is set in the bundle options", p.Name(), r.Path))
+			continue
+		}
+
+		contents, err := opts.ReadFile(r.Path)
+		if err != nil {
+			errors = multierror.Append(errors,
+				fmt.Errorf("policy %q: %v", p.Name(), err))
+			continue
+		}
+
+		b.Files[filePath] = contents
+	}
+
+	if errors != nil {
+		return nil, errors
+	}
+
+	return &b, nil
+}
+
+// resourcePath converts a resource URL, e.g. jsc://file.js, into its
+// path in the bundle. URLs that could resolve outside the bundle's
+// resources, e.g. jsc://../x.js, are rejected, as are paths that aren't
+// in their cleaned form.
+func (b *Bundle) resourcePath(url string) (string, error) {
+	parts := strings.Split(url, "://")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", fmt.Errorf("invalid resource URL %q", url)
+	}
+
+	if strings.ContainsAny(parts[0], `/\.`) {
+		return "", fmt.Errorf("invalid resource type in URL %q", url)
+	}
+
+	name := parts[1]
+	if path.IsAbs(name) || filepath.IsAbs(name) || strings.HasPrefix(name, `\`) {
+		return "", fmt.Errorf("resource URL %q must use a relative path", url)
+	}
+
+	for _, segment := range strings.FieldsFunc(name, func(r rune) bool { return r == '/' || r == '\\' }) {
+		if segment == ".." {
+			return "", fmt.Errorf("resource URL %q must not contain ..", url)
+		}
+	}
+
+	if path.Clean(name) != name || filepath.ToSlash(filepath.Clean(filepath.FromSlash(name))) != name {
+		return "", fmt.Errorf("resource URL %q must use a clean path, e.g. %s://%s",
+			url, parts[0], path.Clean(name))
+	}
+
+	return path.Join(b.Dir, "resources", parts[0], name), nil
+}
+
+// resourceError gives a resource URL error the position of the policy
+// using the resource, when there is one.
+func resourceError(p policy.Namer, err error) error {
+	positioner, ok := p.(policy.Positioner)
+	if !ok {
+		return err
+	}
+
+	return &hclerror.PosError{
+		Pos: positioner.Position(),
+		Err: fmt.Errorf("policy %q: %v", p.Name(), err),
+	}
+}
+
+func (b *Bundle) addXML(p string, v interface{}) error {
+	output, err := xml.MarshalIndent(v, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	b.Files[p] = []byte(xml.Header + string(output))
+	return nil
+}
+
+// Paths returns the paths of all files in the bundle, sorted.
+func (b *Bundle) Paths() []string {
+	var paths []string
+	for p := range b.Files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	return paths
+}
+
+// AddResourcesDir adds every file under dir to the bundle's resources,
+// e.g. dir/jsc/file.js becomes apiproxy/resources/jsc/file.js. Resources
+// already in the bundle, such as inline policy content, are kept.
+func (b *Bundle) AddResourcesDir(dir string) error {
+	return filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+
+		name := path.Join(b.Dir, "resources", filepath.ToSlash(rel))
+		if _, ok := b.Files[name]; ok {
+			return nil
+		}
+
+		contents, err := ioutil.ReadFile(p)
+		if err != nil {
+			return err
+		}
+
+		b.Files[name] = contents
+		return nil
+	})
+}
+
+// WriteDir writes the bundle's files under dir.
+func (b *Bundle) WriteDir(dir string) error {
+	for _, p := range b.Paths() {
+		filePath := filepath.Join(dir, filepath.FromSlash(p))
+		if err := os.MkdirAll(filepath.Dir(filePath), 0777); err != nil {
+			return err
+		}
+
+		if err := ioutil.WriteFile(filePath, b.Files[p], 0666); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WriteZip writes the bundle as a zip archive. Entries are sorted and
+// carry a fixed timestamp, so the output is deterministic.
+func (b *Bundle) WriteZip(w io.Writer) error {
+	zw := zip.NewWriter(w)
+
+	for _, p := range b.Paths() {
+		header := &zip.FileHeader{
+			Name:   p,
+			Method: zip.Deflate,
+		}
+		header.SetModTime(zipModTime)
+		header.SetMode(0644)
+
+		entry, err := zw.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+
+		if _, err := entry.Write(b.Files[p]); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
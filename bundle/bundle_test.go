@@ -0,0 +1,96 @@
+package bundle
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+// TestWriteZipDeterministic builds each fixture twice and expects
+// byte-identical zips, whatever order the sources, policies and
+// resources are visited in.
+func TestWriteZipDeterministic(t *testing.T) {
+	fixtures := []string{
+		"assign_message.hcl",
+		"java_callout.hcl",
+		"module.hcl",
+		"oauth_v2.hcl",
+		"shared_flow.hcl",
+		"transformation.hcl",
+	}
+
+	opts := &Options{ReadFile: ioutil.ReadFile, ReadDir: ioutil.ReadDir}
+
+	for _, fixture := range fixtures {
+		filename := filepath.Join("..", "test-fixtures", fixture)
+		src, err := ioutil.ReadFile(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var zips [2]bytes.Buffer
+		for i := range zips {
+			b, err := Build(map[string][]byte{filename: src}, opts)
+			if err != nil {
+				t.Fatalf("%s: %v", fixture, err)
+			}
+
+			if err := b.WriteZip(&zips[i]); err != nil {
+				t.Fatalf("%s: %v", fixture, err)
+			}
+		}
+
+		if !bytes.Equal(zips[0].Bytes(), zips[1].Bytes()) {
+			t.Errorf("%s: zips of two builds differ", fixture)
+		}
+	}
+}
+
+func TestResourcePath(t *testing.T) {
+	b := Bundle{Dir: "apiproxy"}
+
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"jsc://file.js", "apiproxy/resources/jsc/file.js"},
+		{"jsc://lib/file.js", "apiproxy/resources/jsc/lib/file.js"},
+		{"java://header-callout.jar", "apiproxy/resources/java/header-callout.jar"},
+
+		{"file.js", ""},
+		{"jsc://", ""},
+		{"://file.js", ""},
+		{"jsc://../../x.js", ""},
+		{"xsl://../../../etc/foo", ""},
+		{"jsc://lib/../../x.js", ""},
+		{"jsc://..", ""},
+		{"jsc:///etc/passwd", ""},
+		{`jsc://\x.js`, ""},
+		{`jsc://..\x.js`, ""},
+		{"jsc://./file.js", ""},
+		{"jsc://lib//file.js", ""},
+		{"jsc://lib/", ""},
+		{"../jsc://file.js", ""},
+		{"..://file.js", ""},
+	}
+
+	for _, test := range tests {
+		got, err := b.resourcePath(test.url)
+		if test.want == "" {
+			if err == nil {
+				t.Errorf("resourcePath(%q) = %q, want an error", test.url, got)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("resourcePath(%q): %v", test.url, err)
+			continue
+		}
+
+		if got != test.want {
+			t.Errorf("resourcePath(%q) = %q, want %q", test.url, got, test.want)
+		}
+	}
+}
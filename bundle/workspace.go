@@ -0,0 +1,174 @@
+package bundle
+
+import (
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/kevinswiber/apigee-hcl/dsl"
+	"github.com/kevinswiber/apigee-hcl/dsl/hclerror"
+	"path/filepath"
+	"strings"
+)
+
+// Project is a single proxy or shared flow in a workspace, along with
+// the sources it's built from.
+type Project struct {
+	// Name is the name of the proxy or shared flow, or empty when the
+	// sources don't define one.
+	Name string
+
+	// SharedFlow is true when the project is a shared flow.
+	SharedFlow bool
+
+	// Dir is the directory of the file that defines the project.
+	Dir string
+
+	// Sources maps filenames to HCL sources.
+	Sources map[string][]byte
+}
+
+// String returns a description of the project for messages, e.g.
+// proxy "hello".
+func (p *Project) String() string {
+	if p.SharedFlow {
+		return fmt.Sprintf("shared flow %q", p.Name)
+	}
+	return fmt.Sprintf("proxy %q", p.Name)
+}
+
+// Workspace splits HCL sources, keyed by filename, into one Project per
+// proxy or shared flow, in filename order.
+//
+// A file that defines a proxy or shared flow belongs to that project
+// only. Any other file belongs to every project defined in its
+// directory or below it, e.g. policies next to a proxy file belong to
+// that proxy and variables at the top of the workspace to all of them.
+// A file with no project in or below its directory belongs to every
+// project.
+//
+// Sources that define at most one proxy or shared flow form a single
+// project with every source, as if they were passed to Build.
+func Workspace(sources map[string][]byte) ([]*Project, error) {
+	var errors *multierror.Error
+
+	files := sortedFiles(sources)
+
+	lists, err := parse(sources)
+	if err != nil {
+		return nil, err
+	}
+
+	var projects []*Project
+	isDefinition := make(map[string]bool)
+	seen := make(map[string]*ast.ObjectItem)
+
+	for i, file := range files {
+		items := definitions(lists[i])
+		if len(items) == 0 {
+			continue
+		}
+		isDefinition[file] = true
+
+		for j, item := range items {
+			if j > 0 {
+				errors = multierror.Append(errors, &hclerror.PosError{
+					Pos: item.Val.Pos(),
+					Err: fmt.Errorf("a file may only define one proxy or shared flow, move it to its own file"),
+				})
+				continue
+			}
+
+			// A missing name is reported when the project is decoded.
+			var name string
+			if len(item.Keys) > 0 {
+				name, _ = item.Keys[0].Token.Value().(string)
+			}
+
+			if first, ok := seen[name]; ok && name != "" {
+				errors = multierror.Append(errors, &hclerror.PosError{
+					Pos: item.Pos(),
+					Err: fmt.Errorf("duplicate proxy or shared flow %q, first defined at %s, line %d",
+						name, first.Pos().Filename, first.Pos().Line),
+				})
+				continue
+			}
+			seen[name] = item
+
+			projects = append(projects, &Project{
+				Name:       name,
+				SharedFlow: len(lists[i].Filter("shared_flow").Items) > 0,
+				Dir:        filepath.Dir(file),
+				Sources:    map[string][]byte{file: sources[file]},
+			})
+		}
+	}
+
+	if errors != nil {
+		return nil, errors
+	}
+
+	if len(projects) <= 1 {
+		p := &Project{Dir: ".", Sources: sources}
+		if len(projects) == 1 {
+			p.Name = projects[0].Name
+			p.SharedFlow = projects[0].SharedFlow
+			p.Dir = projects[0].Dir
+		}
+		return []*Project{p}, nil
+	}
+
+	for _, file := range files {
+		if isDefinition[file] {
+			continue
+		}
+
+		dir := filepath.Dir(file)
+
+		var owners []*Project
+		for _, p := range projects {
+			if within(p.Dir, dir) {
+				owners = append(owners, p)
+			}
+		}
+
+		if len(owners) == 0 {
+			owners = projects
+		}
+
+		for _, p := range owners {
+			p.Sources[file] = sources[file]
+		}
+	}
+
+	return projects, nil
+}
+
+// Variables returns the sorted names of the variables declared in HCL
+// sources.
+func Variables(sources map[string][]byte) ([]string, error) {
+	lists, err := parse(sources)
+	if err != nil {
+		return nil, err
+	}
+
+	return dsl.Variables(lists), nil
+}
+
+// definitions returns the proxy and shared_flow blocks in list.
+func definitions(list *ast.ObjectList) []*ast.ObjectItem {
+	var items []*ast.ObjectItem
+	items = append(items, list.Filter("proxy").Items...)
+	items = append(items, list.Filter("shared_flow").Items...)
+
+	return items
+}
+
+// within reports whether dir is parent or one of its subdirectories.
+func within(dir, parent string) bool {
+	rel, err := filepath.Rel(parent, dir)
+	if err != nil {
+		return false
+	}
+
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
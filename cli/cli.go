@@ -1,20 +1,16 @@
 package cli
 
 import (
-	"encoding/xml"
 	"fmt"
-	"github.com/crufter/copyrecur"
 	"github.com/hashicorp/go-multierror"
-	"github.com/hashicorp/hcl"
-	"github.com/hashicorp/hcl/hcl/ast"
-	hclParser "github.com/hashicorp/hcl/hcl/parser"
+	"github.com/kevinswiber/apigee-hcl/bundle"
 	"github.com/kevinswiber/apigee-hcl/dsl"
-	"github.com/kevinswiber/apigee-hcl/dsl/hclerror"
 	"io/ioutil"
 	"log"
 	"os"
 	"path"
-	"strings"
+	"path/filepath"
+	"sort"
 )
 
 // InputValues is an array of input files
@@ -36,224 +32,312 @@ type Options struct {
 	InputHCL      InputValues
 	BuildPath     string
 	ResourcesPath string
+	Zip           bool
+	Vars          InputValues
+	VarFiles      InputValues
+	Environment   string
+	AllEnvs       bool
 }
 
-// Start runs the command line utility logic.
-func Start(opts *Options) {
-	var errors error
-	l := log.New(os.Stderr, "", 0)
+// Start runs the command line utility logic. Inputs that define
+// several proxies or shared flows are built as a workspace, each into
+// its own directory under the build path.
+func Start(opts *Options) error {
+	sources, err := readSources(opts.InputHCL)
+	if err != nil {
+		return err
+	}
 
-	buildPath := opts.BuildPath
-	bundlePath := path.Join(buildPath, "apiproxy")
-	proxyEndpointsPath := path.Join(bundlePath, "proxies")
-	targetEndpointsPath := path.Join(bundlePath, "targets")
-	policiesPath := path.Join(bundlePath, "policies")
-	resourcesPath := path.Join(bundlePath, "resources")
+	inputs, err := readInputs(opts.VarFiles, opts.Vars)
+	if err != nil {
+		return err
+	}
 
-	if err := os.RemoveAll(bundlePath); err != nil {
-		errors = multierror.Append(errors, err)
-		l.Fatal(errors)
+	if opts.AllEnvs && opts.Environment != "" {
+		return fmt.Errorf("-env and -all-envs can't be used together")
 	}
 
-	var c dsl.Config
+	projects, err := bundle.Workspace(sources)
+	if err != nil {
+		return err
+	}
 
-	for _, file := range opts.InputHCL {
-		d, err := ioutil.ReadFile(file)
-		if err != nil {
-			errors = multierror.Append(err)
-			l.Fatal(errors)
+	projectInputs, err := workspaceInputs(projects, inputs)
+	if err != nil {
+		return err
+	}
+
+	if !opts.AllEnvs {
+		return buildProjects(projects, projectInputs, opts.Environment, opts, opts.BuildPath)
+	}
+
+	envs, err := bundle.Environments(sources)
+	if err != nil {
+		return err
+	}
+
+	if len(envs) == 0 {
+		return fmt.Errorf("no environment definitions found")
+	}
+
+	for _, env := range envs {
+		if err := buildProjects(projects, projectInputs, env, opts, path.Join(opts.BuildPath, env)); err != nil {
+			return fmt.Errorf("environment %q: %v", env, err)
 		}
+	}
+
+	return nil
+}
+
+// workspaceInputs returns the inputs for each project. In a workspace,
+// a project only gets the variables it declares, but every input must
+// still be declared by some project.
+func workspaceInputs(projects []*bundle.Project, inputs dsl.Inputs) ([]dsl.Inputs, error) {
+	if len(projects) == 1 {
+		return []dsl.Inputs{inputs}, nil
+	}
 
-		hclRoot, err := hcl.Parse(string(d))
+	var result []dsl.Inputs
+	declared := make(map[string]bool)
+
+	for _, p := range projects {
+		names, err := bundle.Variables(p.Sources)
 		if err != nil {
-			switch err.(type) {
-			case *hclParser.PosError:
-				e := err.(*hclParser.PosError)
-				e2 := &hclerror.PosError{
-					Pos: e.Pos,
-					Err: e.Err,
-				}
-				e2.Pos.Filename = file
-				errors = multierror.Append(errors, e2)
-			default:
-				errors = multierror.Append(errors, err)
+			return nil, err
+		}
+
+		in := make(dsl.Inputs)
+		for _, name := range names {
+			declared[name] = true
+			if v, ok := inputs[name]; ok {
+				in[name] = v
 			}
-			l.Fatal(errors)
 		}
+		result = append(result, in)
+	}
+
+	var undeclared []string
+	for name := range inputs {
+		if !declared[name] {
+			undeclared = append(undeclared, name)
+		}
+	}
+
+	if len(undeclared) > 0 {
+		sort.Strings(undeclared)
 
-		list, ok := hclRoot.Node.(*ast.ObjectList)
-		if !ok {
+		var errors *multierror.Error
+		for _, name := range undeclared {
 			errors = multierror.Append(errors,
-				fmt.Errorf("file doesn't contain root object"))
-			l.Fatal(errors)
+				fmt.Errorf("variable %q was set but is not declared", name))
 		}
+		return nil, errors
+	}
 
-		cfg, err := dsl.DecodeConfigHCL(list)
-		if err != nil {
-			if merr, ok := err.(*multierror.Error); ok {
-				attachFilenameToPosErrors(file, merr)
-			}
-			errors = multierror.Append(errors, err)
-			l.Fatal(errors)
+	return result, nil
+}
+
+// buildProjects builds every project before writing any of them, so an
+// error in one project leaves the build path untouched, and reports the
+// errors from all of them together. A single
+// project is written to buildPath and uses the resources path; in a
+// workspace, each project is written to buildPath/<name> and uses the
+// resources directory next to the file that defines it.
+func buildProjects(projects []*bundle.Project, inputs []dsl.Inputs, env string, opts *Options, buildPath string) error {
+	l := log.New(os.Stderr, "", 0)
+
+	var errors *multierror.Error
+	var bundles []*bundle.Bundle
+	var buildPaths []string
+
+	for i, p := range projects {
+		bundleOpts := &bundle.Options{
+			Inputs:      inputs[i],
+			Environment: env,
+			ReadFile:    ioutil.ReadFile,
+			ReadDir:     ioutil.ReadDir,
 		}
 
-		if cfg.Proxy != nil && cfg.Proxy.Name != "" {
-			c.Proxy = cfg.Proxy
+		prefix := ""
+		if opts.AllEnvs {
+			prefix = env + ": "
 		}
 
-		c.ProxyEndpoints = append(c.ProxyEndpoints, cfg.ProxyEndpoints...)
-		c.TargetEndpoints = append(c.TargetEndpoints, cfg.TargetEndpoints...)
-		c.Policies = append(c.Policies, cfg.Policies...)
+		resourcesPath := opts.ResourcesPath
+		projectPath := buildPath
 
-		if cfg.Resources != nil {
-			if c.Resources == nil {
-				c.Resources = make(map[string]string)
-			}
-			for k, v := range cfg.Resources {
-				c.Resources[k] = v
+		if len(projects) > 1 {
+			prefix += p.String() + ": "
+			resourcesPath = path.Join(p.Dir, "resources")
+			projectPath = path.Join(buildPath, p.Name)
+		}
+
+		b, err := build(p.Sources, bundleOpts, resourcesPath)
+		if err != nil {
+			if len(projects) > 1 {
+				err = prefixErrors(p.String()+": ", err)
 			}
+			errors = multierror.Append(errors, err)
+			continue
 		}
-	}
 
-	// Validate
+		for _, w := range b.Warnings {
+			l.Printf("warning: %s%s", prefix, w)
+		}
 
-	if c.Proxy == nil {
-		errors = multierror.Append(errors,
-			fmt.Errorf("no proxy definition found"))
+		bundles = append(bundles, b)
+		buildPaths = append(buildPaths, projectPath)
 	}
 
-	if len(c.ProxyEndpoints) == 0 {
-		errors = multierror.Append(errors,
-			fmt.Errorf("no proxy endpoint definition found"))
+	if errors != nil {
+		return errors
 	}
 
-	if errors != nil {
-		l.Fatal(errors)
+	for i, b := range bundles {
+		if err := writeBundle(b, buildPaths[i], opts.Zip); err != nil {
+			return err
+		}
 	}
 
-	// Output
+	return nil
+}
 
-	output, err := xml.MarshalIndent(c.Proxy, "", "    ")
-	if err != nil {
-		errors = multierror.Append(errors, err)
-		l.Fatal(errors)
+// prefixErrors prefixes err, or each error in a multierror, with
+// prefix.
+func prefixErrors(prefix string, err error) error {
+	merr, ok := err.(*multierror.Error)
+	if !ok {
+		return fmt.Errorf("%s%v", prefix, err)
 	}
 
-	if err := ensureDirectory(bundlePath); err != nil {
-		errors = multierror.Append(errors, err)
-		l.Fatal(errors)
+	var errors *multierror.Error
+	for _, e := range merr.Errors {
+		errors = multierror.Append(errors, fmt.Errorf("%s%v", prefix, e))
 	}
 
-	apiProxyPath := path.Join(bundlePath, c.Proxy.Name+".xml")
+	return errors
+}
 
-	output = []byte(xml.Header + string(output))
-	if err := ioutil.WriteFile(apiProxyPath, output, 0666); err != nil {
-		errors = multierror.Append(errors, err)
-		l.Fatal(errors)
+func build(sources map[string][]byte, bundleOpts *bundle.Options, resourcesPath string) (*bundle.Bundle, error) {
+	b, err := bundle.Build(sources, bundleOpts)
+	if err != nil {
+		return nil, err
 	}
 
-	if len(c.ProxyEndpoints) > 0 {
-		if err := ensureDirectory(proxyEndpointsPath); err != nil {
-			errors = multierror.Append(errors, err)
-			l.Fatal(errors)
+	if stat, err := os.Stat(resourcesPath); err == nil && stat.IsDir() {
+		if err := b.AddResourcesDir(resourcesPath); err != nil {
+			return nil, err
 		}
+	}
 
-		for _, proxyEndpoint := range c.ProxyEndpoints {
-			var output []byte
-			output, err := xml.MarshalIndent(proxyEndpoint, "", "    ")
-			if err != nil {
-				errors = multierror.Append(errors, err)
-				l.Fatal(errors)
-			}
+	return b, nil
+}
 
-			output = []byte(xml.Header + string(output))
-			ePath := path.Join(proxyEndpointsPath, proxyEndpoint.Name+".xml")
-			if err := ioutil.WriteFile(ePath, output, 0666); err != nil {
-				errors = multierror.Append(errors, err)
-				l.Fatal(errors)
+// readSources reads the given HCL files. A directory is read as a
+// workspace: every .hcl file under it is included, except in modules
+// directories, which are only read through module blocks.
+func readSources(files []string) (map[string][]byte, error) {
+	sources := make(map[string][]byte)
+	for _, file := range files {
+		if stat, err := os.Stat(file); err == nil && stat.IsDir() {
+			if err := readSourcesDir(file, sources); err != nil {
+				return nil, err
 			}
+			continue
 		}
-	}
 
-	if len(c.TargetEndpoints) > 0 {
-		if err := ensureDirectory(targetEndpointsPath); err != nil {
-			errors = multierror.Append(errors, err)
-			l.Fatal(errors)
+		d, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
 		}
+		sources[file] = d
+	}
 
-		for _, targetEndpoint := range c.TargetEndpoints {
-			var output []byte
-			output, err := xml.MarshalIndent(targetEndpoint, "", "    ")
-			if err != nil {
-				errors = multierror.Append(errors, err)
-				l.Fatal(errors)
-			}
+	return sources, nil
+}
 
-			output = []byte(xml.Header + string(output))
-			ePath := path.Join(targetEndpointsPath, targetEndpoint.Name+".xml")
-			if err := ioutil.WriteFile(ePath, output, 0666); err != nil {
-				errors = multierror.Append(errors, err)
-				l.Fatal(errors)
-			}
+func readSourcesDir(dir string, sources map[string][]byte) error {
+	return filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() && info.Name() == "modules" {
+			return filepath.SkipDir
+		}
+		if info.IsDir() || filepath.Ext(p) != ".hcl" {
+			return nil
 		}
-	}
 
-	if len(c.Policies) > 0 {
-		if err := ensureDirectory(policiesPath); err != nil {
-			errors = multierror.Append(errors, err)
-			l.Fatal(errors)
+		d, err := ioutil.ReadFile(p)
+		if err != nil {
+			return err
 		}
 
-		for _, policy := range c.Policies {
-			var output []byte
-			output, err := xml.MarshalIndent(policy, "", "    ")
-			if err != nil {
-				errors = multierror.Append(errors, err)
-				l.Fatal(errors)
-			}
+		sources[p] = d
+		return nil
+	})
+}
 
-			name := policy.Name()
+// readInputs reads variable values from var files, in order, and then
+// from name=value pairs, so later values override earlier ones.
+func readInputs(varFiles, vars []string) (dsl.Inputs, error) {
+	inputs := make(dsl.Inputs)
 
-			output = []byte(xml.Header + string(output))
-			ePath := path.Join(policiesPath, name+".xml")
-			if err := ioutil.WriteFile(ePath, output, 0666); err != nil {
-				errors = multierror.Append(errors, err)
-				l.Fatal(errors)
-			}
+	for _, file := range varFiles {
+		d, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
 		}
-	}
 
-	if stat, err := os.Stat(opts.ResourcesPath); err == nil {
-		if stat.IsDir() {
-			if err := copyrecur.CopyDir(opts.ResourcesPath, resourcesPath); err != nil {
-				errors = multierror.Append(errors, err)
-				l.Fatal(errors)
-			}
+		values, err := dsl.ParseVarFile(file, d)
+		if err != nil {
+			return nil, err
+		}
+
+		for k, v := range values {
+			inputs[k] = v
 		}
 	}
 
-	if c.Resources != nil {
-		if err := ensureDirectory(resourcesPath); err != nil {
-			errors = multierror.Append(errors, err)
-			l.Fatal(errors)
+	for _, v := range vars {
+		if err := inputs.Set(v); err != nil {
+			return nil, err
 		}
+	}
 
-		for fileName, content := range c.Resources {
-			parts := strings.Split(fileName, "://")
-			lang := parts[0]
-			dir := path.Join(resourcesPath, lang)
-			if err := ensureDirectory(dir); err != nil {
-				errors = multierror.Append(errors, err)
-				l.Fatal(errors)
-			}
+	return inputs, nil
+}
 
-			fileContents := []byte(content)
-			if err := ioutil.WriteFile(path.Join(dir, parts[1]), fileContents, 0666); err != nil {
-				errors = multierror.Append(errors, err)
-				l.Fatal(errors)
-			}
+// writeBundle replaces the bundle's directory, or its <name>.zip file
+// with zip, in buildPath. Output of the other kind is left alone.
+func writeBundle(b *bundle.Bundle, buildPath string, zip bool) error {
+	if !zip {
+		if err := os.RemoveAll(path.Join(buildPath, b.Dir)); err != nil {
+			return err
 		}
+		return b.WriteDir(buildPath)
 	}
+
+	if err := ensureDirectory(buildPath); err != nil {
+		return err
+	}
+
+	zipPath := path.Join(buildPath, b.Name+".zip")
+	if err := os.Remove(zipPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	f, err := os.Create(zipPath)
+	if err != nil {
+		return err
+	}
+
+	if err := b.WriteZip(f); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
 }
 
 func ensureDirectory(path string) error {
@@ -269,16 +353,3 @@ func ensureDirectory(path string) error {
 		return err
 	}
 }
-
-func attachFilenameToPosErrors(file string, errors *multierror.Error) {
-	for _, e := range errors.Errors {
-		switch e.(type) {
-		case *hclerror.PosError:
-		case *hclParser.PosError:
-			e2 := e.(*hclerror.PosError)
-			e2.Pos.Filename = file
-		case *multierror.Error:
-			attachFilenameToPosErrors(file, e.(*multierror.Error))
-		}
-	}
-}
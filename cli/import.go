@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"github.com/kevinswiber/apigee-hcl/importer"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+)
+
+// ImportOptions is an arguments container for importing a proxy bundle.
+type ImportOptions struct {
+	BundlePath    string
+	OutputHCL     string
+	ResourcesPath string
+}
+
+// Import converts an exported Apigee proxy bundle into HCL.
+//
+// Resources that aren't inlined into a policy are written to
+// ResourcesPath, so they are picked up again when compiling the HCL.
+func Import(opts *ImportOptions) error {
+	c, err := importer.ReadBundle(opts.BundlePath)
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = os.Stdout
+	if opts.OutputHCL != "" {
+		f, err := os.Create(opts.OutputHCL)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if err := importer.WriteHCL(out, c); err != nil {
+		return err
+	}
+
+	for fileName, content := range c.Resources {
+		parts := strings.Split(fileName, "://")
+		lang := parts[0]
+		dir := path.Join(opts.ResourcesPath, lang)
+		if err := ensureDirectory(dir); err != nil {
+			return err
+		}
+
+		if err := ioutil.WriteFile(path.Join(dir, parts[1]), content, 0666); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"github.com/kevinswiber/apigee-hcl/bundle"
+	"github.com/kevinswiber/apigee-hcl/simulate"
+	"io/ioutil"
+	"os"
+)
+
+// SimulateOptions is an arguments container for simulating a request.
+type SimulateOptions struct {
+	InputHCL    InputValues
+	Request     string
+	Vars        InputValues
+	VarFiles    InputValues
+	Environment string
+}
+
+// Simulate traces a request through the proxy described by the input
+// HCL and writes the trace to stdout.
+func Simulate(opts *SimulateOptions) error {
+	sources, err := readSources(opts.InputHCL)
+	if err != nil {
+		return err
+	}
+
+	inputs, err := readInputs(opts.VarFiles, opts.Vars)
+	if err != nil {
+		return err
+	}
+
+	bundleOpts := &bundle.Options{
+		Inputs:      inputs,
+		Environment: opts.Environment,
+		ReadFile:    ioutil.ReadFile,
+		ReadDir:     ioutil.ReadDir,
+	}
+
+	c, err := bundle.Decode(sources, bundleOpts)
+	if err != nil {
+		return err
+	}
+
+	src, err := ioutil.ReadFile(opts.Request)
+	if err != nil {
+		return err
+	}
+
+	r, err := simulate.DecodeRequest(opts.Request, src)
+	if err != nil {
+		return err
+	}
+
+	t, err := simulate.Run(c, r)
+	if err != nil {
+		return err
+	}
+
+	return t.Write(os.Stdout)
+}
@@ -0,0 +1,272 @@
+package condition
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Canonical comparison operators. Every symbol and keyword form of an
+// operator (e.g. "=", "==", "Equals", "Is") parses to one of these.
+//
+// Documentation: http://docs.apigee.com/api-services/reference/conditions-reference#operators
+const (
+	Equals                = "Equals"
+	NotEquals             = "NotEquals"
+	EqualsCaseInsensitive = "EqualsCaseInsensitive"
+	GreaterThan           = "GreaterThan"
+	GreaterThanOrEquals   = "GreaterThanOrEquals"
+	LesserThan            = "LesserThan"
+	LesserThanOrEquals    = "LesserThanOrEquals"
+	Matches               = "Matches"
+	JavaRegex             = "JavaRegex"
+	MatchesPath           = "MatchesPath"
+	StartsWith            = "StartsWith"
+)
+
+// Expr is a node in a parsed condition.
+type Expr interface {
+	String() string
+	expr()
+}
+
+// And is a logical conjunction of two conditions.
+type And struct {
+	Left, Right Expr
+}
+
+// Or is a logical disjunction of two conditions.
+type Or struct {
+	Left, Right Expr
+}
+
+// Not is a negated condition.
+type Not struct {
+	Expr Expr
+}
+
+// Comparison compares two operands, e.g. request.verb = "GET". A bare
+// operand is a Comparison with an empty Operator and a nil Right.
+type Comparison struct {
+	Operator string
+	Left     *Operand
+	Right    *Operand
+}
+
+// OperandKind identifies the kind of an Operand.
+type OperandKind int
+
+// Operand kinds
+const (
+	Variable OperandKind = iota
+	String
+	Number
+	Null
+	Boolean
+)
+
+// Operand is a flow variable reference or a literal value.
+type Operand struct {
+	Kind  OperandKind
+	Value string
+}
+
+func (*And) expr()        {}
+func (*Or) expr()         {}
+func (*Not) expr()        {}
+func (*Comparison) expr() {}
+
+func (e *And) String() string {
+	return fmt.Sprintf("(%s and %s)", e.Left, e.Right)
+}
+
+func (e *Or) String() string {
+	return fmt.Sprintf("(%s or %s)", e.Left, e.Right)
+}
+
+func (e *Not) String() string {
+	return fmt.Sprintf("not %s", e.Expr)
+}
+
+func (e *Comparison) String() string {
+	if e.Right == nil {
+		return e.Left.String()
+	}
+	return fmt.Sprintf("%s %s %s", e.Left, e.Operator, e.Right)
+}
+
+func (o *Operand) String() string {
+	if o.Kind == String {
+		return strconv.Quote(o.Value)
+	}
+	return o.Value
+}
+
+// SyntaxError is returned for a malformed condition.
+type SyntaxError struct {
+	Offset int
+	Msg    string
+}
+
+// Error implements the error interface
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s at column %d", e.Msg, e.Offset+1)
+}
+
+// Parse parses an Apigee condition expression.
+//
+// Documentation: http://docs.apigee.com/api-services/reference/conditions-reference
+func Parse(src string) (Expr, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := parser{tokens: tokens}
+	if p.peek().Type == tokenEOF {
+		return nil, &SyntaxError{Offset: 0, Msg: "empty condition"}
+	}
+
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.Type != tokenEOF {
+		return nil, p.unexpected(t)
+	}
+
+	return e, nil
+}
+
+type parser struct {
+	tokens []lexToken
+	pos    int
+}
+
+func (p *parser) peek() lexToken {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() lexToken {
+	t := p.tokens[p.pos]
+	if t.Type != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) unexpected(t lexToken) error {
+	if t.Type == tokenEOF {
+		return &SyntaxError{Offset: t.Offset, Msg: "unexpected end of condition"}
+	}
+	return &SyntaxError{Offset: t.Offset, Msg: fmt.Sprintf("unexpected %q", t.Text)}
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().Type == tokenOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &Or{Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().Type == tokenAnd {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &And{Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	if p.peek().Type == tokenNot {
+		p.next()
+		e, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Expr: e}, nil
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Expr, error) {
+	if t := p.peek(); t.Type == tokenLParen {
+		p.next()
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if closing := p.next(); closing.Type != tokenRParen {
+			if closing.Type == tokenEOF {
+				return nil, &SyntaxError{Offset: t.Offset, Msg: "unbalanced parenthesis"}
+			}
+			return nil, p.unexpected(closing)
+		}
+
+		return e, nil
+	}
+
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.peek().Type != tokenOperator {
+		return &Comparison{Left: left}, nil
+	}
+
+	op := p.next()
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Comparison{Operator: op.Value, Left: left, Right: right}, nil
+}
+
+func (p *parser) parseOperand() (*Operand, error) {
+	t := p.next()
+	switch t.Type {
+	case tokenString:
+		return &Operand{Kind: String, Value: t.Value}, nil
+	case tokenNumber:
+		if _, err := strconv.ParseFloat(t.Value, 64); err != nil {
+			return nil, &SyntaxError{Offset: t.Offset, Msg: fmt.Sprintf("invalid number %q", t.Text)}
+		}
+		return &Operand{Kind: Number, Value: t.Value}, nil
+	case tokenIdent:
+		switch strings.ToLower(t.Value) {
+		case "null":
+			return &Operand{Kind: Null, Value: "null"}, nil
+		case "true", "false":
+			return &Operand{Kind: Boolean, Value: strings.ToLower(t.Value)}, nil
+		}
+		return &Operand{Kind: Variable, Value: t.Value}, nil
+	}
+
+	return nil, p.unexpected(t)
+}
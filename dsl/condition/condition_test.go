@@ -0,0 +1,111 @@
+package condition
+
+import (
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		// Operators, in their symbol and keyword forms
+		{`request.verb = "GET"`, `request.verb Equals "GET"`},
+		{`request.verb == "GET"`, `request.verb Equals "GET"`},
+		{`request.verb Is "GET"`, `request.verb Equals "GET"`},
+		{`request.verb equals "GET"`, `request.verb Equals "GET"`},
+		{`request.verb != "GET"`, `request.verb NotEquals "GET"`},
+		{`request.verb IsNot "GET"`, `request.verb NotEquals "GET"`},
+		{`name := "bob"`, `name EqualsCaseInsensitive "bob"`},
+		{`count > 1`, `count GreaterThan 1`},
+		{`count >= 1`, `count GreaterThanOrEquals 1`},
+		{`count < 1.5`, `count LesserThan 1.5`},
+		{`count <= 1`, `count LesserThanOrEquals 1`},
+		{`count LesserThanOrEquals 1`, `count LesserThanOrEquals 1`},
+		{`path ~ "/v1/*"`, `path Matches "/v1/*"`},
+		{`path Like "/v1/*"`, `path Matches "/v1/*"`},
+		{`path ~~ "\d+"`, `path JavaRegex "\\d+"`},
+		{`path ~/ "/v1/**"`, `path MatchesPath "/v1/**"`},
+		{`path LikePath "/v1/**"`, `path MatchesPath "/v1/**"`},
+		{`path =| "/v1"`, `path StartsWith "/v1"`},
+
+		// Operands
+		{`flag`, `flag`},
+		{`flag = TRUE`, `flag Equals true`},
+		{`flag = False`, `flag Equals false`},
+		{`flag = NULL`, `flag Equals null`},
+		{`name = 'it\'s'`, `name Equals "it's"`},
+		{`name = "a \"quoted\" \\ word"`, `name Equals "a \"quoted\" \\ word"`},
+		{`request.header.x-api-key`, `request.header.x-api-key`},
+		{`request.queryparam.ids[0]`, `request.queryparam.ids[0]`},
+
+		// Precedence: not binds tighter than and, and tighter than or
+		{`a and b or c`, `((a and b) or c)`},
+		{`a or b and c`, `(a or (b and c))`},
+		{`a || b && c`, `(a or (b and c))`},
+		{`a AND b OR c`, `((a and b) or c)`},
+		{`a and b and c`, `((a and b) and c)`},
+		{`a or b or c`, `((a or b) or c)`},
+		{`not a and b`, `(not a and b)`},
+		{`!a or b`, `(not a or b)`},
+		{`not not a`, `not not a`},
+
+		// Parentheses
+		{`(a or b) and c`, `((a or b) and c)`},
+		{`a and (b or c)`, `(a and (b or c))`},
+		{`not (a and b)`, `not (a and b)`},
+		{`((a))`, `a`},
+		{`(request.verb = "GET") and (count > 1 or flag)`, `(request.verb Equals "GET" and (count GreaterThan 1 or flag))`},
+	}
+
+	for _, test := range tests {
+		e, err := Parse(test.src)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", test.src, err)
+			continue
+		}
+
+		if got := e.String(); got != test.want {
+			t.Errorf("Parse(%q) = %s, want %s", test.src, got, test.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{``, `empty condition at column 1`},
+		{`   `, `empty condition at column 1`},
+		{`(a and b`, `unbalanced parenthesis at column 1`},
+		{`a and (b or c`, `unbalanced parenthesis at column 7`},
+		{`a)`, `unexpected ")" at column 2`},
+		{`()`, `unexpected ")" at column 2`},
+		{`a and`, `unexpected end of condition at column 6`},
+		{`not`, `unexpected end of condition at column 4`},
+		{`a =`, `unexpected end of condition at column 4`},
+		{`= a`, `unexpected "=" at column 1`},
+		{`a b`, `unexpected "b" at column 3`},
+		{`a = b = c`, `unexpected "=" at column 7`},
+		{`a = "b`, `unterminated string literal at column 5`},
+		{`a # b`, `unexpected character '#' at column 3`},
+		{`a = 1.2.3`, `invalid number "1.2.3" at column 5`},
+	}
+
+	for _, test := range tests {
+		_, err := Parse(test.src)
+		if err == nil {
+			t.Errorf("Parse(%q) succeeded, want error %q", test.src, test.want)
+			continue
+		}
+
+		if _, ok := err.(*SyntaxError); !ok {
+			t.Errorf("Parse(%q) returned %T, want *SyntaxError", test.src, err)
+		}
+
+		if got := err.Error(); got != test.want {
+			t.Errorf("Parse(%q) error = %q, want %q", test.src, got, test.want)
+		}
+	}
+}
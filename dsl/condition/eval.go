@@ -0,0 +1,177 @@
+package condition
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Lookup resolves a flow variable. It returns false if the variable
+// isn't set, in which case the variable evaluates to null.
+type Lookup func(name string) (string, bool)
+
+// Eval evaluates a parsed condition against a set of flow variables.
+//
+// An unquoted right-hand operand that doesn't resolve to a variable is
+// compared as a literal, e.g. request.verb = GET.
+func Eval(e Expr, lookup Lookup) (bool, error) {
+	switch e := e.(type) {
+	case *And:
+		left, err := Eval(e.Left, lookup)
+		if err != nil || !left {
+			return false, err
+		}
+		return Eval(e.Right, lookup)
+	case *Or:
+		left, err := Eval(e.Left, lookup)
+		if err != nil || left {
+			return left, err
+		}
+		return Eval(e.Right, lookup)
+	case *Not:
+		result, err := Eval(e.Expr, lookup)
+		return !result, err
+	case *Comparison:
+		return evalComparison(e, lookup)
+	}
+
+	return false, fmt.Errorf("unknown expression %T", e)
+}
+
+// EvalString parses and evaluates a condition. An empty condition is
+// always true.
+func EvalString(src string, lookup Lookup) (bool, error) {
+	if strings.TrimSpace(src) == "" {
+		return true, nil
+	}
+
+	e, err := Parse(src)
+	if err != nil {
+		return false, err
+	}
+
+	return Eval(e, lookup)
+}
+
+func evalComparison(c *Comparison, lookup Lookup) (bool, error) {
+	left, leftOK := resolve(c.Left, lookup, false)
+
+	if c.Right == nil {
+		return leftOK && left != "" && !strings.EqualFold(left, "false"), nil
+	}
+
+	right, rightOK := resolve(c.Right, lookup, true)
+
+	if !leftOK || !rightOK {
+		switch c.Operator {
+		case Equals:
+			return leftOK == rightOK, nil
+		case NotEquals:
+			return leftOK != rightOK, nil
+		}
+		return false, nil
+	}
+
+	switch c.Operator {
+	case Equals:
+		return compare(left, right) == 0, nil
+	case NotEquals:
+		return compare(left, right) != 0, nil
+	case EqualsCaseInsensitive:
+		return strings.EqualFold(left, right), nil
+	case GreaterThan:
+		return compare(left, right) > 0, nil
+	case GreaterThanOrEquals:
+		return compare(left, right) >= 0, nil
+	case LesserThan:
+		return compare(left, right) < 0, nil
+	case LesserThanOrEquals:
+		return compare(left, right) <= 0, nil
+	case StartsWith:
+		return strings.HasPrefix(left, right), nil
+	case Matches:
+		return matchPattern(wildcardPattern(right), left)
+	case MatchesPath:
+		return matchPattern(pathPattern(right), left)
+	case JavaRegex:
+		return matchPattern(right, left)
+	}
+
+	return false, fmt.Errorf("unknown operator %q", c.Operator)
+}
+
+func resolve(o *Operand, lookup Lookup, literalFallback bool) (string, bool) {
+	switch o.Kind {
+	case Null:
+		return "", false
+	case Variable:
+		if v, ok := lookup(o.Value); ok {
+			return v, true
+		}
+		if literalFallback {
+			return o.Value, true
+		}
+		return "", false
+	}
+
+	return o.Value, true
+}
+
+// compare compares two values numerically when both are numbers and
+// as strings otherwise.
+func compare(a, b string) int {
+	x, errA := strconv.ParseFloat(a, 64)
+	y, errB := strconv.ParseFloat(b, 64)
+	if errA == nil && errB == nil {
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	}
+
+	if (strings.EqualFold(a, "true") || strings.EqualFold(a, "false")) && strings.EqualFold(a, b) {
+		return 0
+	}
+
+	return strings.Compare(a, b)
+}
+
+func matchPattern(pattern, s string) (bool, error) {
+	re, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		return false, fmt.Errorf("invalid pattern %q: %v", pattern, err)
+	}
+
+	return re.MatchString(s), nil
+}
+
+// wildcardPattern converts a Matches pattern, where "*" matches any
+// characters, into a regular expression.
+func wildcardPattern(p string) string {
+	parts := strings.Split(p, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+
+	return strings.Join(parts, ".*")
+}
+
+// pathPattern converts a MatchesPath pattern, where "*" matches a single
+// path segment and "**" matches any number of segments, into a regular
+// expression.
+func pathPattern(p string) string {
+	var buf []string
+	for _, segment := range strings.Split(p, "**") {
+		parts := strings.Split(segment, "*")
+		for i, part := range parts {
+			parts[i] = regexp.QuoteMeta(part)
+		}
+		buf = append(buf, strings.Join(parts, "[^/]*"))
+	}
+
+	return strings.Join(buf, ".*")
+}
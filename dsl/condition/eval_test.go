@@ -0,0 +1,129 @@
+package condition
+
+import (
+	"strings"
+	"testing"
+)
+
+var testVariables = map[string]string{
+	"request.verb":     "GET",
+	"proxy.pathsuffix": "/v1/cats/42",
+	"count":            "10",
+	"flag":             "false",
+	"name":             "Bob",
+	"empty":            "",
+}
+
+func testLookup(name string) (string, bool) {
+	v, ok := testVariables[name]
+	return v, ok
+}
+
+func TestEvalString(t *testing.T) {
+	tests := []struct {
+		src  string
+		want bool
+	}{
+		// An empty condition always passes
+		{``, true},
+		{`  `, true},
+
+		// Equality
+		{`request.verb = "GET"`, true},
+		{`request.verb = "POST"`, false},
+		{`request.verb != "POST"`, true},
+		{`request.verb = GET`, true},
+		{`request.verb = "get"`, false},
+		{`request.verb := "get"`, true},
+		{`flag = FALSE`, true},
+		{`count = 10.0`, true},
+		{`count = "10"`, true},
+
+		// Numbers compare numerically, anything else as strings
+		{`count > 9`, true},
+		{`count > 10`, false},
+		{`count >= 10`, true},
+		{`count < 9.5`, false},
+		{`count <= 10`, true},
+		{`name > "Al"`, true},
+		{`name < "Al"`, false},
+
+		// Patterns
+		{`proxy.pathsuffix =| "/v1"`, true},
+		{`proxy.pathsuffix =| "/v2"`, false},
+		{`proxy.pathsuffix ~ "/v1/*"`, true},
+		{`proxy.pathsuffix ~ "/v1/cats"`, false},
+		{`proxy.pathsuffix ~/ "/v1/*"`, false},
+		{`proxy.pathsuffix ~/ "/v1/*/*"`, true},
+		{`proxy.pathsuffix ~/ "/v1/**"`, true},
+		{`proxy.pathsuffix ~/ "/v1/**/42"`, true},
+		{`proxy.pathsuffix ~~ "/v1/cats/\d+"`, true},
+		{`proxy.pathsuffix ~~ "/v1/cats"`, false},
+		{`name ~ "B.b"`, false},
+
+		// Unset variables are null
+		{`missing = null`, true},
+		{`missing != null`, false},
+		{`name = null`, false},
+		{`name != null`, true},
+		{`missing > 1`, false},
+		{`missing ~ "*"`, false},
+
+		// Bare operands are true when set, non-empty and not false
+		{`name`, true},
+		{`flag`, false},
+		{`empty`, false},
+		{`missing`, false},
+		{`not missing`, true},
+		{`true`, true},
+		{`false`, false},
+
+		// Precedence and parentheses
+		{`true or true and false`, true},
+		{`(true or true) and false`, false},
+		{`not true and false`, false},
+		{`not (true and false)`, true},
+		{`false or not false`, true},
+		{`request.verb = "GET" and (count > 100 or name = "Bob")`, true},
+		{`request.verb = "POST" or count > 100 or flag`, false},
+
+		// The right-hand side isn't evaluated when the left decides
+		{`false and name ~~ "("`, false},
+		{`true or name ~~ "("`, true},
+	}
+
+	for _, test := range tests {
+		got, err := EvalString(test.src, testLookup)
+		if err != nil {
+			t.Errorf("EvalString(%q): %v", test.src, err)
+			continue
+		}
+
+		if got != test.want {
+			t.Errorf("EvalString(%q) = %t, want %t", test.src, got, test.want)
+		}
+	}
+}
+
+func TestEvalStringErrors(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{`name ~~ "("`, `invalid pattern "("`},
+		{`true and name ~~ "[a-"`, `invalid pattern "[a-"`},
+		{`name = `, `unexpected end of condition`},
+	}
+
+	for _, test := range tests {
+		_, err := EvalString(test.src, testLookup)
+		if err == nil {
+			t.Errorf("EvalString(%q) succeeded, want error %q", test.src, test.want)
+			continue
+		}
+
+		if !strings.Contains(err.Error(), test.want) {
+			t.Errorf("EvalString(%q) error = %q, want %q", test.src, err, test.want)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package condition
+
+import (
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/hashicorp/hcl/hcl/token"
+	"github.com/kevinswiber/apigee-hcl/dsl/hclerror"
+)
+
+// hclKeys are the HCL attributes that hold condition expressions.
+var hclKeys = map[string]bool{
+	"condition":             true,
+	"skip_cache_lookup":     true,
+	"skip_cache_population": true,
+}
+
+// CheckHCL parses every condition attribute in an HCL ast.ObjectList and
+// returns an hclerror.PosError for each one that is malformed.
+func CheckHCL(list *ast.ObjectList) error {
+	var errors *multierror.Error
+
+	ast.Walk(list, func(n ast.Node) (ast.Node, bool) {
+		item, ok := n.(*ast.ObjectItem)
+		if !ok || len(item.Keys) != 1 {
+			return n, true
+		}
+
+		if !hclKeys[item.Keys[0].Token.Text] {
+			return n, true
+		}
+
+		lit, ok := item.Val.(*ast.LiteralType)
+		if !ok || (lit.Token.Type != token.STRING && lit.Token.Type != token.HEREDOC) {
+			return n, true
+		}
+
+		src, ok := lit.Token.Value().(string)
+		if !ok || src == "" {
+			return n, true
+		}
+
+		if _, err := Parse(src); err != nil {
+			errors = multierror.Append(errors, &hclerror.PosError{
+				Pos: lit.Pos(),
+				Err: fmt.Errorf("invalid %s %q: %v", item.Keys[0].Token.Text, src, err),
+			})
+		}
+
+		return n, true
+	})
+
+	if errors != nil {
+		return errors
+	}
+
+	return nil
+}
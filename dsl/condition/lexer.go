@@ -0,0 +1,199 @@
+package condition
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenType int
+
+const (
+	tokenEOF tokenType = iota
+	tokenLParen
+	tokenRParen
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenOperator
+	tokenString
+	tokenNumber
+	tokenIdent
+)
+
+type lexToken struct {
+	Type   tokenType
+	Text   string
+	Value  string
+	Offset int
+}
+
+// symbolOperators are ordered so that longer operators match first.
+var symbolOperators = []struct {
+	text     string
+	typ      tokenType
+	operator string
+}{
+	{"&&", tokenAnd, ""},
+	{"||", tokenOr, ""},
+	{"==", tokenOperator, Equals},
+	{"=|", tokenOperator, StartsWith},
+	{"!=", tokenOperator, NotEquals},
+	{":=", tokenOperator, EqualsCaseInsensitive},
+	{"~~", tokenOperator, JavaRegex},
+	{"~/", tokenOperator, MatchesPath},
+	{">=", tokenOperator, GreaterThanOrEquals},
+	{"<=", tokenOperator, LesserThanOrEquals},
+	{"=", tokenOperator, Equals},
+	{"!", tokenNot, ""},
+	{"~", tokenOperator, Matches},
+	{">", tokenOperator, GreaterThan},
+	{"<", tokenOperator, LesserThan},
+}
+
+// wordOperators maps lowercased operator keywords to their canonical
+// operator. Keywords are matched case-insensitively.
+var wordOperators = map[string]string{
+	"equals":                Equals,
+	"is":                    Equals,
+	"notequals":             NotEquals,
+	"isnot":                 NotEquals,
+	"equalscaseinsensitive": EqualsCaseInsensitive,
+	"greaterthan":           GreaterThan,
+	"greaterthanorequals":   GreaterThanOrEquals,
+	"lesserthan":            LesserThan,
+	"lesserthanorequals":    LesserThanOrEquals,
+	"matches":               Matches,
+	"like":                  Matches,
+	"javaregex":             JavaRegex,
+	"matchespath":           MatchesPath,
+	"likepath":              MatchesPath,
+	"startswith":            StartsWith,
+}
+
+func lex(src string) ([]lexToken, error) {
+	var tokens []lexToken
+
+	i := 0
+	for i < len(src) {
+		c := src[i]
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+			continue
+		case c == '(':
+			tokens = append(tokens, lexToken{Type: tokenLParen, Text: "(", Offset: i})
+			i++
+			continue
+		case c == ')':
+			tokens = append(tokens, lexToken{Type: tokenRParen, Text: ")", Offset: i})
+			i++
+			continue
+		case c == '"' || c == '\'':
+			t, n, err := lexString(src, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, t)
+			i += n
+			continue
+		case c >= '0' && c <= '9':
+			start := i
+			for i < len(src) && (isDigit(src[i]) || src[i] == '.') {
+				i++
+			}
+			text := src[start:i]
+			tokens = append(tokens, lexToken{Type: tokenNumber, Text: text, Value: text, Offset: start})
+			continue
+		case isIdentStart(c):
+			start := i
+			for i < len(src) && isIdentPart(src[i]) {
+				i++
+			}
+			tokens = append(tokens, wordToken(src[start:i], start))
+			continue
+		}
+
+		matched := false
+		for _, op := range symbolOperators {
+			if strings.HasPrefix(src[i:], op.text) {
+				tokens = append(tokens, lexToken{
+					Type:   op.typ,
+					Text:   op.text,
+					Value:  op.operator,
+					Offset: i,
+				})
+				i += len(op.text)
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			return nil, &SyntaxError{Offset: i, Msg: fmt.Sprintf("unexpected character %q", c)}
+		}
+	}
+
+	tokens = append(tokens, lexToken{Type: tokenEOF, Offset: len(src)})
+	return tokens, nil
+}
+
+func lexString(src string, start int) (lexToken, int, error) {
+	quote := src[start]
+	var value []byte
+
+	i := start + 1
+	for i < len(src) {
+		c := src[i]
+		// Only escaped quotes and backslashes are unescaped, so regular
+		// expressions such as "\d+" keep their backslashes.
+		if c == '\\' && i+1 < len(src) && (src[i+1] == quote || src[i+1] == '\\') {
+			value = append(value, src[i+1])
+			i += 2
+			continue
+		}
+		if c == quote {
+			return lexToken{
+				Type:   tokenString,
+				Text:   src[start : i+1],
+				Value:  string(value),
+				Offset: start,
+			}, i + 1 - start, nil
+		}
+		value = append(value, c)
+		i++
+	}
+
+	return lexToken{}, 0, &SyntaxError{Offset: start, Msg: "unterminated string literal"}
+}
+
+func wordToken(word string, offset int) lexToken {
+	lower := strings.ToLower(word)
+	switch lower {
+	case "and":
+		return lexToken{Type: tokenAnd, Text: word, Offset: offset}
+	case "or":
+		return lexToken{Type: tokenOr, Text: word, Offset: offset}
+	case "not":
+		return lexToken{Type: tokenNot, Text: word, Offset: offset}
+	}
+
+	if op, ok := wordOperators[lower]; ok {
+		return lexToken{Type: tokenOperator, Text: word, Value: op, Offset: offset}
+	}
+
+	return lexToken{Type: tokenIdent, Text: word, Value: word, Offset: offset}
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || isDigit(c) || c == '.' || c == '-' ||
+		c == '[' || c == ']'
+}
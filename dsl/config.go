@@ -4,18 +4,23 @@ import (
 	"fmt"
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/kevinswiber/apigee-hcl/dsl/condition"
 	"github.com/kevinswiber/apigee-hcl/dsl/endpoints"
 	"github.com/kevinswiber/apigee-hcl/dsl/hclerror"
 	"github.com/kevinswiber/apigee-hcl/dsl/policies/policy"
+	"strconv"
+	"strings"
 )
 
-// Config is a container for holding the contents of an exported Apigee proxy bundle
+// Config is a container for holding the contents of an exported Apigee proxy
+// bundle, or of a shared flow bundle when SharedFlow is set.
 type Config struct {
 	Proxy           *Proxy
+	SharedFlow      *SharedFlowBundle
 	ProxyEndpoints  []*endpoints.ProxyEndpoint
 	TargetEndpoints []*endpoints.TargetEndpoint
 	Policies        []policy.Namer
-	Resources       map[string]string
+	Resources       map[string][]byte
 }
 
 // DecodeConfigHCL converts an HCL ast.ObjectList into a Config object
@@ -24,14 +29,26 @@ func DecodeConfigHCL(list *ast.ObjectList) (*Config, error) {
 
 	var c Config
 
+	if err := condition.CheckHCL(list); err != nil {
+		errors = multierror.Append(errors, err)
+	}
+
 	if proxies := list.Filter("proxy"); len(proxies.Items) > 0 {
 		result, err := decodeProxyHCL(proxies)
 		if err != nil {
 			errors = multierror.Append(errors, err)
-			return nil, errors
+		} else {
+			c.Proxy = result
 		}
+	}
 
-		c.Proxy = result
+	if sharedFlows := list.Filter("shared_flow"); len(sharedFlows.Items) > 0 {
+		result, err := decodeSharedFlowHCL(sharedFlows)
+		if err != nil {
+			errors = multierror.Append(errors, err)
+		} else {
+			c.SharedFlow = result
+		}
 	}
 
 	if proxyEndpoints := list.Filter("proxy_endpoint"); len(proxyEndpoints.Items) > 0 {
@@ -40,7 +57,7 @@ func DecodeConfigHCL(list *ast.ObjectList) (*Config, error) {
 			proxyEndpoint, err := endpoints.DecodeProxyEndpointHCL(item)
 			if err != nil {
 				errors = multierror.Append(errors, err)
-				return nil, errors
+				continue
 			}
 			result = append(result, proxyEndpoint)
 		}
@@ -54,7 +71,7 @@ func DecodeConfigHCL(list *ast.ObjectList) (*Config, error) {
 			targetEndpoint, err := endpoints.DecodeTargetEndpointHCL(item)
 			if err != nil {
 				errors = multierror.Append(errors, err)
-				return nil, errors
+				continue
 			}
 			result = append(result, targetEndpoint)
 		}
@@ -80,32 +97,148 @@ func DecodeConfigHCL(list *ast.ObjectList) (*Config, error) {
 			}
 			policyType := item.Keys[0].Token.Value().(string)
 
-			if f, ok := PolicyList[policyType]; ok {
-				p, err := f(item)
-				if err != nil {
-					errors = multierror.Append(errors, err)
-				}
+			f, ok := PolicyList[policyType]
+			if !ok {
+				errors = multierror.Append(errors, unknownPolicyTypeError(item))
+				continue
+			}
+
+			p, err := f(item)
+			if err != nil {
+				errors = multierror.Append(errors, err)
+				continue
+			}
 
-				switch p.(type) {
-				case policy.Resourcer:
-					resourcePolicy := p.(policy.Resourcer)
-					r := resourcePolicy.Resource()
-					if len(r.URL) > 0 && len(r.Content) > 0 {
-						if c.Resources == nil {
-							c.Resources = make(map[string]string)
-						}
-						c.Resources[r.URL] = r.Content
+			switch p.(type) {
+			case policy.Resourcer:
+				resourcePolicy := p.(policy.Resourcer)
+				r := resourcePolicy.Resource()
+				if len(r.URL) > 0 && len(r.Content) > 0 {
+					if c.Resources == nil {
+						c.Resources = make(map[string][]byte)
 					}
+					c.Resources[r.URL] = []byte(r.Content)
 				}
-				ps = append(ps, p.(policy.Namer))
 			}
-		}
-
-		if errors != nil {
-			return nil, errors
+			ps = append(ps, p.(policy.Namer))
 		}
 
 		c.Policies = ps
 	}
+
+	if errors != nil {
+		return nil, errors
+	}
+
 	return &c, nil
 }
+
+// Merge adds the definitions in other to the Config. A named proxy or
+// shared flow definition in other replaces the current one; callers
+// that don't expect a second definition should check for it first.
+func (c *Config) Merge(other *Config) {
+	if other.Proxy != nil && other.Proxy.Name != "" {
+		c.Proxy = other.Proxy
+	}
+
+	if other.SharedFlow != nil {
+		c.SharedFlow = other.SharedFlow
+	}
+
+	c.ProxyEndpoints = append(c.ProxyEndpoints, other.ProxyEndpoints...)
+	c.TargetEndpoints = append(c.TargetEndpoints, other.TargetEndpoints...)
+	c.Policies = append(c.Policies, other.Policies...)
+
+	if other.Resources != nil {
+		if c.Resources == nil {
+			c.Resources = make(map[string][]byte)
+		}
+		for k, v := range other.Resources {
+			c.Resources[k] = v
+		}
+	}
+}
+
+// Steps returns every step in every flow and fault rule of the Config's
+// endpoints, and the steps of its shared flow.
+func (c *Config) Steps() []*endpoints.FlowStep {
+	var result []*endpoints.FlowStep
+
+	if c.SharedFlow != nil && c.SharedFlow.Flow != nil {
+		result = append(result, c.SharedFlow.Flow.Steps...)
+	}
+
+	for _, e := range c.ProxyEndpoints {
+		if e.PreFlow != nil {
+			result = append(result, e.PreFlow.Request.Steps...)
+			result = append(result, e.PreFlow.Response.Steps...)
+		}
+		for _, f := range e.Flows {
+			result = append(result, f.Request.Steps...)
+			result = append(result, f.Response.Steps...)
+		}
+		if e.PostFlow != nil {
+			result = append(result, e.PostFlow.Request.Steps...)
+			result = append(result, e.PostFlow.Response.Steps...)
+		}
+		if e.PostClientFlow != nil {
+			result = append(result, e.PostClientFlow.Request.Steps...)
+			result = append(result, e.PostClientFlow.Response.Steps...)
+		}
+		for _, r := range e.FaultRules {
+			result = append(result, r.Steps...)
+		}
+		if e.DefaultFaultRule != nil {
+			result = append(result, e.DefaultFaultRule.Steps...)
+		}
+	}
+
+	for _, e := range c.TargetEndpoints {
+		if e.PreFlow != nil {
+			result = append(result, e.PreFlow.Request.Steps...)
+			result = append(result, e.PreFlow.Response.Steps...)
+		}
+		for _, f := range e.Flows {
+			result = append(result, f.Request.Steps...)
+			result = append(result, f.Response.Steps...)
+		}
+		if e.PostFlow != nil {
+			result = append(result, e.PostFlow.Request.Steps...)
+			result = append(result, e.PostFlow.Response.Steps...)
+		}
+		if e.EventFlow != nil {
+			result = append(result, e.EventFlow.Response.Steps...)
+		}
+		for _, r := range e.FaultRules {
+			result = append(result, r.Steps...)
+		}
+		if e.DefaultFaultRule != nil {
+			result = append(result, e.DefaultFaultRule.Steps...)
+		}
+	}
+
+	return result
+}
+
+func unknownPolicyTypeError(item *ast.ObjectItem) error {
+	policyType := item.Keys[0].Token.Value().(string)
+	msg := fmt.Sprintf("unknown policy type %q", policyType)
+
+	if suggestions := suggestPolicyTypes(policyType); len(suggestions) > 0 {
+		quoted := make([]string, len(suggestions))
+		for i, s := range suggestions {
+			quoted[i] = strconv.Quote(s)
+		}
+
+		if len(quoted) == 1 {
+			msg += fmt.Sprintf(", did you mean %s?", quoted[0])
+		} else {
+			msg += fmt.Sprintf(", did you mean one of %s?", strings.Join(quoted, ", "))
+		}
+	}
+
+	return &hclerror.PosError{
+		Pos: item.Keys[0].Pos(),
+		Err: fmt.Errorf("%s", msg),
+	}
+}
@@ -1,10 +1,14 @@
 package endpoints
 
 import (
+	"encoding/xml"
 	"fmt"
-
+	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/hcl"
 	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/hashicorp/hcl/hcl/token"
+	"github.com/kevinswiber/apigee-hcl/dsl/hclerror"
+	"reflect"
 )
 
 // PreFlow represents a <PreFlow/> element for
@@ -22,11 +26,39 @@ type PreFlow struct {
 //
 // Documentation: http://docs.apigee.com/api-services/reference/api-proxy-configuration-reference#flows
 type Flow struct {
-	XMLName   string       `xml:"Flow" hcl:"-"`
-	Name      string       `xml:"name,attr" hcl:"-"`
-	Condition string       `xml:",omitempty" hcl:"condition"`
-	Request   FlowRequest  `hcl:"request"`
-	Response  FlowResponse `hcl:"response"`
+	XMLName     string       `xml:"Flow" hcl:"-"`
+	Name        string       `xml:"name,attr" hcl:"-"`
+	Description string       `xml:",omitempty" hcl:"description"`
+	Condition   string       `xml:",omitempty" hcl:"condition"`
+	Request     FlowRequest  `hcl:"request"`
+	Response    FlowResponse `hcl:"response"`
+}
+
+// FlowList holds the conditional flows of a ProxyEndpoint or
+// TargetEndpoint. It's written as a <Flows/> element, which is left out
+// when there are no flows; encoding/xml always writes the parent of a
+// Flows>Flow field.
+type FlowList []*Flow
+
+// MarshalXML writes the flows inside a <Flows/> element.
+func (l FlowList) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name.Local = "Flows"
+	return e.EncodeElement(struct {
+		Flows []*Flow `xml:"Flow"`
+	}{l}, start)
+}
+
+// UnmarshalXML reads the flows in a <Flows/> element.
+func (l *FlowList) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var v struct {
+		Flows []*Flow `xml:"Flow"`
+	}
+	if err := d.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+
+	*l = v.Flows
+	return nil
 }
 
 // PostFlow represents a <PostFlow/> element for
@@ -49,6 +81,18 @@ type PostClientFlow struct {
 	Response FlowResponse `hcl:"response"`
 }
 
+// EventFlow represents an <EventFlow/> element for
+// TargetEndpoint definitions. It runs for each server-sent event in
+// a streamed response.
+//
+// Documentation: https://cloud.google.com/apigee/docs/api-platform/develop/server-sent-events
+type EventFlow struct {
+	XMLName     string       `xml:"EventFlow" hcl:"-"`
+	Name        string       `xml:"name,attr,omitempty" hcl:"-"`
+	ContentType string       `xml:"content-type,attr,omitempty" hcl:"content_type"`
+	Response    FlowResponse `hcl:"response"`
+}
+
 // FaultRule represents a <FaultRule/> element for
 // ProxyEndpoint and TargetEndpoint definitions.
 //
@@ -57,7 +101,33 @@ type FaultRule struct {
 	XMLName   string      `xml:"FaultRule" hcl:"-"`
 	Name      string      `xml:"name,attr" hcl:"-"`
 	Condition string      `xml:",omitempty" hcl:"condition"`
-	Steps     []*FlowStep `xml:",innerxml" hcl:"step"`
+	Steps     []*FlowStep `xml:"Step" hcl:"step"`
+}
+
+// FaultRuleList holds the fault rules of a ProxyEndpoint or
+// TargetEndpoint. Like FlowList, its <FaultRules/> element is left out
+// when there are none.
+type FaultRuleList []*FaultRule
+
+// MarshalXML writes the fault rules inside a <FaultRules/> element.
+func (l FaultRuleList) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name.Local = "FaultRules"
+	return e.EncodeElement(struct {
+		FaultRules []*FaultRule `xml:"FaultRule"`
+	}{l}, start)
+}
+
+// UnmarshalXML reads the fault rules in a <FaultRules/> element.
+func (l *FaultRuleList) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var v struct {
+		FaultRules []*FaultRule `xml:"FaultRule"`
+	}
+	if err := d.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+
+	*l = v.FaultRules
+	return nil
 }
 
 // DefaultFaultRule represents a <DefaultFaultRule/> element for
@@ -68,7 +138,7 @@ type DefaultFaultRule struct {
 	XMLName       string      `xml:"DefaultFaultRule" hcl:"-"`
 	Name          string      `xml:"name,attr" hcl:"-"`
 	Condition     string      `xml:",omitempty" hcl:"condition"`
-	Steps         []*FlowStep `xml:",innerxml" hcl:"step"`
+	Steps         []*FlowStep `xml:"Step" hcl:"step"`
 	AlwaysEnforce bool        `xml:",omitempty" hcl:"always_enforce"`
 }
 
@@ -80,7 +150,8 @@ type DefaultFaultRule struct {
 type FlowStep struct {
 	XMLName   string `xml:"Step"`
 	Name      string
-	Condition string `xml:",omitempty" hcl:"condition"`
+	Condition string    `xml:",omitempty" hcl:"condition"`
+	Pos       token.Pos `xml:"-" hcl:"-"`
 }
 
 // FlowRequest represents a <Request/> element for
@@ -89,7 +160,7 @@ type FlowStep struct {
 // Documentation: http://docs.apigee.com/api-services/reference/api-proxy-configuration-reference#watchaquickhowtovideo-flowconfigurationelements
 type FlowRequest struct {
 	XMLName string      `xml:"Request" hcl:"-"`
-	Steps   []*FlowStep `xml:",innerxml" hcl:"step"`
+	Steps   []*FlowStep `xml:"Step" hcl:"step"`
 }
 
 // FlowResponse represents a <Response/> element for
@@ -98,217 +169,314 @@ type FlowRequest struct {
 // Documentation: http://docs.apigee.com/api-services/reference/api-proxy-configuration-reference#watchaquickhowtovideo-flowconfigurationelements
 type FlowResponse struct {
 	XMLName string      `xml:"Response" hcl:"-"`
-	Steps   []*FlowStep `xml:",innerxml" hcl:"step"`
+	Steps   []*FlowStep `xml:"Step" hcl:"step"`
 }
 
-func decodePreFlowHCL(list *ast.ObjectList) (*PreFlow, error) {
-	var result PreFlow
-	item := list.Items[0]
+// flowBlocks maps the HCL flow blocks to the endpoint fields they
+// decode into. An endpoint that lacks a field doesn't allow the block.
+var flowBlocks = []struct {
+	key    string
+	field  string
+	decode func(*ast.ObjectList) (interface{}, error)
+}{
+	{"pre_flow", "PreFlow", decodePreFlowHCL},
+	{"flow", "Flows", decodeFlowsHCL},
+	{"post_flow", "PostFlow", decodePostFlowHCL},
+	{"post_client_flow", "PostClientFlow", decodePostClientFlowHCL},
+	{"event_flow", "EventFlow", decodeEventFlowHCL},
+	{"fault_rule", "FaultRules", decodeFaultRulesHCL},
+	{"default_fault_rule", "DefaultFaultRule", decodeDefaultFaultRuleHCL},
+}
 
-	var listVal *ast.ObjectList
-	if ot, ok := item.Val.(*ast.ObjectType); ok {
-		listVal = ot.List
-	} else {
-		return nil, fmt.Errorf("pre flow item not an object")
-	}
+// decodeFlowBlocksHCL decodes the flow blocks in list into the matching
+// fields of endpoint, which must be a pointer to a ProxyEndpoint or a
+// TargetEndpoint.
+func decodeFlowBlocksHCL(endpoint interface{}, kind string, list *ast.ObjectList) error {
+	var errors *multierror.Error
+	v := reflect.ValueOf(endpoint).Elem()
+
+	for _, b := range flowBlocks {
+		items := list.Filter(b.key)
+		if len(items.Items) == 0 {
+			continue
+		}
 
-	if request := listVal.Filter("request"); len(request.Items) > 0 {
-		item := request.Items[0]
+		field := v.FieldByName(b.field)
+		if !field.IsValid() {
+			errors = multierror.Append(errors, &hclerror.PosError{
+				Pos: items.Items[0].Val.Pos(),
+				Err: fmt.Errorf("%s is not allowed in a %s", b.key, kind),
+			})
+			continue
+		}
 
-		steps, err := decodeFlowStepsHCL(item)
+		result, err := b.decode(items)
 		if err != nil {
-			return nil, err
+			errors = multierror.Append(errors, err)
+			continue
 		}
 
-		result.Request.Steps = steps
+		field.Set(reflect.ValueOf(result))
 	}
 
-	if response := listVal.Filter("response"); len(response.Items) > 0 {
-		item := response.Items[0]
+	if errors != nil {
+		return errors
+	}
 
-		steps, err := decodeFlowStepsHCL(item)
-		if err != nil {
-			return nil, err
-		}
+	return nil
+}
+
+func decodePreFlowHCL(list *ast.ObjectList) (interface{}, error) {
+	item, err := single(list, "pre_flow")
+	if err != nil {
+		return nil, err
+	}
 
-		result.Response.Steps = steps
+	var result PreFlow
+	if err := decodeRequestResponseHCL(item, &result.Request, &result.Response); err != nil {
+		return nil, err
 	}
 
 	return &result, nil
 }
 
-func decodeFlowsHCL(list *ast.ObjectList) ([]*Flow, error) {
-	var result []*Flow
-
-	for _, item := range list.Items {
-		var flow Flow
-		var listVal *ast.ObjectList
-		if ot, ok := item.Val.(*ast.ObjectType); ok {
-			listVal = ot.List
-		} else {
-			return nil, fmt.Errorf("pre flow item not an object")
-		}
-
-		if request := listVal.Filter("request"); len(request.Items) > 0 {
-			item := request.Items[0]
+func decodePostFlowHCL(list *ast.ObjectList) (interface{}, error) {
+	item, err := single(list, "post_flow")
+	if err != nil {
+		return nil, err
+	}
 
-			steps, err := decodeFlowStepsHCL(item)
-			if err != nil {
-				return nil, err
-			}
+	var result PostFlow
+	if err := decodeRequestResponseHCL(item, &result.Request, &result.Response); err != nil {
+		return nil, err
+	}
 
-			flow.Request.Steps = steps
-		}
+	return &result, nil
+}
 
-		if response := listVal.Filter("response"); len(response.Items) > 0 {
-			item := response.Items[0]
+func decodePostClientFlowHCL(list *ast.ObjectList) (interface{}, error) {
+	item, err := single(list, "post_client_flow")
+	if err != nil {
+		return nil, err
+	}
 
-			steps, err := decodeFlowStepsHCL(item)
-			if err != nil {
-				return nil, err
-			}
+	var result PostClientFlow
+	if err := decodeRequestResponseHCL(item, &result.Request, &result.Response); err != nil {
+		return nil, err
+	}
 
-			flow.Response.Steps = steps
+	if len(result.Request.Steps) > 0 {
+		return nil, &hclerror.PosError{
+			Pos: item.Val.Pos(),
+			Err: fmt.Errorf("post_client_flow only supports response steps"),
 		}
-
-		flow.Name = item.Keys[0].Token.Value().(string)
-		result = append(result, &flow)
 	}
 
-	return result, nil
+	return &result, nil
 }
 
-func decodePostFlowHCL(list *ast.ObjectList) (*PostFlow, error) {
-	var result PostFlow
-	item := list.Items[0]
-
-	var listVal *ast.ObjectList
-	if ot, ok := item.Val.(*ast.ObjectType); ok {
-		listVal = ot.List
-	} else {
-		return nil, fmt.Errorf("post flow item not an object")
+func decodeEventFlowHCL(list *ast.ObjectList) (interface{}, error) {
+	item, err := single(list, "event_flow")
+	if err != nil {
+		return nil, err
 	}
 
-	if request := listVal.Filter("request"); len(request.Items) > 0 {
-		item := request.Items[0]
+	var result EventFlow
 
-		steps, err := decodeFlowStepsHCL(item)
-		if err != nil {
-			return nil, err
-		}
-
-		result.Request.Steps = steps
+	if err := hcl.DecodeObject(&result, item.Val); err != nil {
+		return nil, err
 	}
 
-	if response := listVal.Filter("response"); len(response.Items) > 0 {
-		item := response.Items[0]
-
-		steps, err := decodeFlowStepsHCL(item)
-		if err != nil {
-			return nil, err
-		}
+	if err := decodeRequestResponseHCL(item, nil, &result.Response); err != nil {
+		return nil, err
+	}
 
-		result.Response.Steps = steps
+	if len(item.Keys) > 0 {
+		result.Name = item.Keys[0].Token.Value().(string)
 	}
 
 	return &result, nil
 }
 
-func decodePostClientFlowHCL(list *ast.ObjectList) (*PostClientFlow, error) {
-	var result PostClientFlow
-	item := list.Items[0]
+func decodeFlowsHCL(list *ast.ObjectList) (interface{}, error) {
+	var errors *multierror.Error
+	var result []*Flow
 
-	var listVal *ast.ObjectList
-	if ot, ok := item.Val.(*ast.ObjectType); ok {
-		listVal = ot.List
-	} else {
-		return nil, fmt.Errorf("post client flow item not an object")
-	}
+	for _, item := range list.Items {
+		if len(item.Keys) == 0 || item.Keys[0].Token.Value() == "" {
+			errors = multierror.Append(errors, &hclerror.PosError{
+				Pos: item.Val.Pos(),
+				Err: fmt.Errorf("flow requires a name"),
+			})
+			continue
+		}
 
-	if request := listVal.Filter("request"); len(request.Items) > 0 {
-		item := request.Items[0]
+		var flow Flow
+		if err := hcl.DecodeObject(&flow, item.Val); err != nil {
+			errors = multierror.Append(errors, err)
+			continue
+		}
 
-		steps, err := decodeFlowStepsHCL(item)
-		if err != nil {
-			return nil, err
+		if err := decodeRequestResponseHCL(item, &flow.Request, &flow.Response); err != nil {
+			errors = multierror.Append(errors, err)
+			continue
 		}
 
-		result.Request.Steps = steps
+		flow.Name = item.Keys[0].Token.Value().(string)
+		result = append(result, &flow)
 	}
 
-	if response := listVal.Filter("response"); len(response.Items) > 0 {
-		item := response.Items[0]
-
-		steps, err := decodeFlowStepsHCL(item)
-		if err != nil {
-			return nil, err
-		}
-
-		result.Response.Steps = steps
+	if errors != nil {
+		return nil, errors
 	}
 
-	return &result, nil
+	return result, nil
 }
 
-func decodeFaultRulesHCL(list *ast.ObjectList) ([]*FaultRule, error) {
+func decodeFaultRulesHCL(list *ast.ObjectList) (interface{}, error) {
+	var errors *multierror.Error
 	var result []*FaultRule
 
 	for _, item := range list.Items {
+		if len(item.Keys) == 0 || item.Keys[0].Token.Value() == "" {
+			errors = multierror.Append(errors, &hclerror.PosError{
+				Pos: item.Val.Pos(),
+				Err: fmt.Errorf("fault rule requires a name"),
+			})
+			continue
+		}
+
 		var faultRule FaultRule
+		if err := hcl.DecodeObject(&faultRule, item.Val); err != nil {
+			errors = multierror.Append(errors, err)
+			continue
+		}
 
-		steps, err := decodeFlowStepsHCL(item)
+		steps, err := DecodeFlowStepsHCL(item)
 		if err != nil {
-			return nil, err
+			errors = multierror.Append(errors, err)
+			continue
 		}
 
 		faultRule.Steps = steps
-
 		faultRule.Name = item.Keys[0].Token.Value().(string)
 		result = append(result, &faultRule)
 	}
 
+	if errors != nil {
+		return nil, errors
+	}
+
 	return result, nil
 }
 
-func decodeDefaultFaultRuleHCL(item *ast.ObjectItem) (*DefaultFaultRule, error) {
+func decodeDefaultFaultRuleHCL(list *ast.ObjectList) (interface{}, error) {
+	item, err := single(list, "default_fault_rule")
+	if err != nil {
+		return nil, err
+	}
+
 	var faultRule DefaultFaultRule
 
 	if err := hcl.DecodeObject(&faultRule, item.Val); err != nil {
-		return nil, fmt.Errorf("error decoding step object")
+		return nil, err
 	}
 
-	steps, err := decodeFlowStepsHCL(item)
+	steps, err := DecodeFlowStepsHCL(item)
 	if err != nil {
 		return nil, err
 	}
 
 	faultRule.Steps = steps
 
-	faultRule.Name = item.Keys[0].Token.Value().(string)
+	if len(item.Keys) > 0 {
+		faultRule.Name = item.Keys[0].Token.Value().(string)
+	}
 
 	return &faultRule, nil
 }
 
-func decodeFlowStepsHCL(list *ast.ObjectItem) ([]*FlowStep, error) {
-	var listVal *ast.ObjectList
-	if ot, ok := list.Val.(*ast.ObjectType); ok {
-		listVal = ot.List
-	} else {
-		return nil, fmt.Errorf("request item not an object")
+// decodeRequestResponseHCL decodes the request and response blocks of
+// a flow. A nil request means the flow has no request block.
+func decodeRequestResponseHCL(item *ast.ObjectItem, request *FlowRequest, response *FlowResponse) error {
+	ot, ok := item.Val.(*ast.ObjectType)
+	if !ok {
+		return &hclerror.PosError{
+			Pos: item.Val.Pos(),
+			Err: fmt.Errorf("flow is not an object"),
+		}
+	}
+
+	if requestList := ot.List.Filter("request"); len(requestList.Items) > 0 {
+		if request == nil {
+			return &hclerror.PosError{
+				Pos: requestList.Items[0].Val.Pos(),
+				Err: fmt.Errorf("request is not allowed in this flow"),
+			}
+		}
+
+		steps, err := DecodeFlowStepsHCL(requestList.Items[0])
+		if err != nil {
+			return err
+		}
+
+		request.Steps = steps
+	}
+
+	if responseList := ot.List.Filter("response"); len(responseList.Items) > 0 {
+		steps, err := DecodeFlowStepsHCL(responseList.Items[0])
+		if err != nil {
+			return err
+		}
+
+		response.Steps = steps
+	}
+
+	return nil
+}
+
+// DecodeFlowStepsHCL converts the step blocks inside an HCL
+// ast.ObjectItem into FlowStep objects.
+func DecodeFlowStepsHCL(item *ast.ObjectItem) ([]*FlowStep, error) {
+	ot, ok := item.Val.(*ast.ObjectType)
+	if !ok {
+		return nil, &hclerror.PosError{
+			Pos: item.Val.Pos(),
+			Err: fmt.Errorf("steps are not in an object"),
+		}
 	}
 
 	var flowSteps []*FlowStep
-	if steps := listVal.Filter("step"); len(steps.Items) > 0 {
-		for _, step := range steps.Items {
-			var s FlowStep
-			if err := hcl.DecodeObject(&s, step.Val); err != nil {
-				return nil, fmt.Errorf("error decoding step object")
+	for _, step := range ot.List.Filter("step").Items {
+		if len(step.Keys) == 0 || step.Keys[0].Token.Value() == "" {
+			return nil, &hclerror.PosError{
+				Pos: step.Pos(),
+				Err: fmt.Errorf("step requires a policy name"),
 			}
-			s.Name = step.Keys[0].Token.Value().(string)
+		}
 
-			flowSteps = append(flowSteps, &s)
+		var s FlowStep
+		if err := hcl.DecodeObject(&s, step.Val); err != nil {
+			return nil, err
 		}
+		s.Name = step.Keys[0].Token.Value().(string)
+		s.Pos = step.Pos()
+
+		flowSteps = append(flowSteps, &s)
 	}
 
 	return flowSteps, nil
 }
+
+// single returns the only item in list, for blocks that may only be
+// defined once per endpoint.
+func single(list *ast.ObjectList, key string) (*ast.ObjectItem, error) {
+	if len(list.Items) > 1 {
+		return nil, &hclerror.PosError{
+			Pos: list.Items[1].Val.Pos(),
+			Err: fmt.Errorf("%s may only be defined once", key),
+		}
+	}
+
+	return list.Items[0], nil
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/hcl"
 	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/hashicorp/hcl/hcl/token"
 	"github.com/kevinswiber/apigee-hcl/dsl/hclerror"
 	"github.com/kevinswiber/apigee-hcl/dsl/properties"
 )
@@ -16,13 +17,14 @@ type ProxyEndpoint struct {
 	XMLName             string               `xml:"ProxyEndpoint" hcl:"-"`
 	Name                string               `xml:"name,attr" hcl:"-"`
 	PreFlow             *PreFlow             `hcl:"pre_flow"`
-	Flows               []*Flow              `xml:"Flows>Flow" hcl:"flows"`
+	Flows               FlowList             `xml:",omitempty" hcl:"flow"`
 	PostFlow            *PostFlow            `hcl:"post_flow"`
 	PostClientFlow      *PostClientFlow      `hcl:"post_client_flow"`
-	FaultRules          []*FaultRule         `xml:"FaultRules>FaultRule" hcl:"fault_rules"`
+	FaultRules          FaultRuleList        `xml:",omitempty" hcl:"fault_rule"`
 	DefaultFaultRule    *DefaultFaultRule    `hcl:"default_fault_rule"`
 	HTTPProxyConnection *HTTPProxyConnection `hcl:"http_proxy_connection"`
 	RouteRules          []*RouteRule         `xml:"RouteRule" hcl:"route_rule"`
+	Pos                 token.Pos            `xml:"-" hcl:"-"`
 }
 
 // HTTPProxyConnection represents an <HTTPProxyConnection/> element
@@ -40,11 +42,12 @@ type HTTPProxyConnection struct {
 //
 // Documentation: http://docs.apigee.com/api-services/reference/api-proxy-configuration-reference#proxyendpoint-proxyendpointconfigurationelements
 type RouteRule struct {
-	XMLName        string `xml:"RouteRule"`
-	Name           string `xml:"name,attr" hcl:"-"`
-	Condition      string `xml:",omitempty" hcl:"condition"`
-	TargetEndpoint string `xml:",omitempty" hcl:"target_endpoint"`
-	URL            string `xml:",omitempty" hcl:"url"`
+	XMLName        string    `xml:"RouteRule"`
+	Name           string    `xml:"name,attr" hcl:"-"`
+	Condition      string    `xml:",omitempty" hcl:"condition"`
+	TargetEndpoint string    `xml:",omitempty" hcl:"target_endpoint"`
+	URL            string    `xml:",omitempty" hcl:"url"`
+	Pos            token.Pos `xml:"-" hcl:"-"`
 }
 
 // DecodeProxyEndpointHCL converts an HCL ast.ObjectItem into a ProxyEndpoint.
@@ -85,59 +88,10 @@ func DecodeProxyEndpointHCL(item *ast.ObjectItem) (*ProxyEndpoint, error) {
 	}
 
 	proxyEndpoint.Name = n
+	proxyEndpoint.Pos = item.Pos()
 
-	if preFlow := listVal.Filter("pre_flow"); len(preFlow.Items) > 0 {
-		preFlow, err := decodePreFlowHCL(preFlow)
-		if err != nil {
-			errors = multierror.Append(errors, err)
-		} else {
-			proxyEndpoint.PreFlow = preFlow
-		}
-	}
-
-	if flows := listVal.Filter("flow"); len(flows.Items) > 0 {
-		flows, err := decodeFlowsHCL(flows)
-		if err != nil {
-			errors = multierror.Append(errors, err)
-		} else {
-			proxyEndpoint.Flows = flows
-		}
-	}
-
-	if postFlow := listVal.Filter("post_flow"); len(postFlow.Items) > 0 {
-		postFlow, err := decodePostFlowHCL(postFlow)
-		if err != nil {
-			errors = multierror.Append(errors, err)
-		} else {
-			proxyEndpoint.PostFlow = postFlow
-		}
-	}
-
-	if postClientFlow := listVal.Filter("post_client_flow"); len(postClientFlow.Items) > 0 {
-		postClientFlow, err := decodePostClientFlowHCL(postClientFlow)
-		if err != nil {
-			errors = multierror.Append(errors, err)
-		} else {
-			proxyEndpoint.PostClientFlow = postClientFlow
-		}
-	}
-
-	if faultRulesList := listVal.Filter("fault_rule"); len(faultRulesList.Items) > 0 {
-		faultRules, err := decodeFaultRulesHCL(faultRulesList)
-		if err != nil {
-			errors = multierror.Append(errors, err)
-		} else {
-			proxyEndpoint.FaultRules = faultRules
-		}
-	}
-
-	if defaultFaultRulesList := listVal.Filter("default_fault_rule"); len(defaultFaultRulesList.Items) > 0 {
-		faultRule, err := decodeDefaultFaultRuleHCL(defaultFaultRulesList.Items[0])
-		if err != nil {
-			errors = multierror.Append(errors, err)
-		} else {
-			proxyEndpoint.DefaultFaultRule = faultRule
-		}
+	if err := decodeFlowBlocksHCL(&proxyEndpoint, "proxy endpoint", listVal); err != nil {
+		errors = multierror.Append(errors, err)
 	}
 
 	if hpcList := listVal.Filter("http_proxy_connection"); len(hpcList.Items) > 0 {
@@ -201,6 +155,7 @@ func decodeRouteRulesHCL(list *ast.ObjectList) ([]*RouteRule, error) {
 			return nil, fmt.Errorf("error decoding route rule object")
 		}
 		rule.Name = item.Keys[0].Token.Value().(string)
+		rule.Pos = item.Pos()
 
 		result = append(result, &rule)
 	}
@@ -1,10 +1,12 @@
 package endpoints
 
 import (
+	"encoding/xml"
 	"fmt"
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/hcl"
 	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/hashicorp/hcl/hcl/token"
 	"github.com/kevinswiber/apigee-hcl/dsl/hclerror"
 	"github.com/kevinswiber/apigee-hcl/dsl/properties"
 )
@@ -16,14 +18,16 @@ type TargetEndpoint struct {
 	XMLName               string                 `xml:"TargetEndpoint" hcl:"-"`
 	Name                  string                 `xml:"name,attr" hcl:"-"`
 	PreFlow               *PreFlow               `hcl:"pre_flow"`
-	Flows                 []*Flow                `xml:"Flows,omitempty>Flow" hcl:"flows"`
+	Flows                 FlowList               `xml:",omitempty" hcl:"flow"`
 	PostFlow              *PostFlow              `hcl:"post_flow"`
-	FaultRules            []*FaultRule           `xml:"FaultRules,omitempty>FaultRule" hcl:"fault_rules"`
+	EventFlow             *EventFlow             `xml:",omitempty" hcl:"event_flow"`
+	FaultRules            FaultRuleList          `xml:",omitempty" hcl:"fault_rule"`
 	DefaultFaultRule      *DefaultFaultRule      `hcl:"default_fault_rule"`
 	HTTPTargetConnection  *HTTPTargetConnection  `hcl:"http_target_connection"`
 	LocalTargetConnection *LocalTargetConnection `xml:",omitempty" hcl:"local_target_connection"`
 	ScriptTarget          *ScriptTarget          `xml:",omitempty" hcl:"script_target"`
 	SSLInfo               *SSLInfo               `xml:",omitempty" hcl:"ssl_info"`
+	Pos                   token.Pos              `xml:"-" hcl:"-"`
 }
 
 // HTTPTargetConnection represents an <HTTPTargetConnection/> element
@@ -94,6 +98,24 @@ type EnvironmentVariable struct {
 	Value   interface{} `xml:",chardata" hcl:"-"`
 }
 
+// UnmarshalXML decodes an <EnvironmentVariable/> element, keeping its value
+// as a string.
+func (e *EnvironmentVariable) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var v struct {
+		Name  string `xml:"name,attr"`
+		Value string `xml:",chardata"`
+	}
+
+	if err := d.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+
+	e.Name = v.Name
+	e.Value = v.Value
+
+	return nil
+}
+
 // LoadBalancerServer represents a <LoadBalancerServer/> element
 // in a LoadBalancer.
 //
@@ -136,50 +158,10 @@ func DecodeTargetEndpointHCL(item *ast.ObjectItem) (*TargetEndpoint, error) {
 	}
 
 	targetEndpoint.Name = n
+	targetEndpoint.Pos = item.Pos()
 
-	if preFlow := listVal.Filter("pre_flow"); len(preFlow.Items) > 0 {
-		preFlow, err := decodePreFlowHCL(preFlow)
-		if err != nil {
-			errors = multierror.Append(errors, err)
-		} else {
-			targetEndpoint.PreFlow = preFlow
-		}
-	}
-
-	if flows := listVal.Filter("flow"); len(flows.Items) > 0 {
-		flows, err := decodeFlowsHCL(flows)
-		if err != nil {
-			errors = multierror.Append(errors, err)
-		} else {
-			targetEndpoint.Flows = flows
-		}
-	}
-
-	if postFlow := listVal.Filter("post_flow"); len(postFlow.Items) > 0 {
-		postFlow, err := decodePostFlowHCL(postFlow)
-		if err != nil {
-			errors = multierror.Append(errors, err)
-		} else {
-			targetEndpoint.PostFlow = postFlow
-		}
-	}
-
-	if faultRulesList := listVal.Filter("fault_rule"); len(faultRulesList.Items) > 0 {
-		faultRules, err := decodeFaultRulesHCL(faultRulesList)
-		if err != nil {
-			errors = multierror.Append(errors, err)
-		} else {
-			targetEndpoint.FaultRules = faultRules
-		}
-	}
-
-	if defaultFaultRulesList := listVal.Filter("default_fault_rule"); len(defaultFaultRulesList.Items) > 0 {
-		faultRule, err := decodeDefaultFaultRuleHCL(defaultFaultRulesList.Items[0])
-		if err != nil {
-			errors = multierror.Append(errors, err)
-		} else {
-			targetEndpoint.DefaultFaultRule = faultRule
-		}
+	if err := decodeFlowBlocksHCL(&targetEndpoint, "target endpoint", listVal); err != nil {
+		errors = multierror.Append(errors, err)
 	}
 
 	if htcList := listVal.Filter("http_target_connection"); len(htcList.Items) > 0 {
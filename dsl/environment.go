@@ -0,0 +1,179 @@
+package dsl
+
+import (
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/kevinswiber/apigee-hcl/dsl/hclerror"
+	"sort"
+)
+
+// Environments returns the sorted names of the environment blocks
+// in lists.
+func Environments(lists []*ast.ObjectList) ([]string, error) {
+	var errors *multierror.Error
+	seen := make(map[string]bool)
+
+	for _, list := range lists {
+		for _, item := range list.Items {
+			if !isEnvironment(item) {
+				continue
+			}
+
+			name, err := environmentName(item)
+			if err != nil {
+				errors = multierror.Append(errors, err)
+				continue
+			}
+			seen[name] = true
+		}
+	}
+
+	if errors != nil {
+		return nil, errors
+	}
+
+	var names []string
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// ApplyEnvironment deep-merges the contents of every environment block
+// with the given name onto the other top-level blocks in lists, then
+// removes all environment blocks. An empty name only removes them.
+//
+// Blocks are matched by their type and labels, e.g.
+// target_endpoint "default". Nested blocks are merged the same way and
+// attributes are replaced. Blocks that don't match anything are added.
+//
+// Example:
+//
+//	environment "prod" {
+//	  target_endpoint "default" {
+//	    http_target_connection {
+//	      url = "https://api.example.com"
+//	    }
+//	  }
+//	}
+func ApplyEnvironment(lists []*ast.ObjectList, name string) error {
+	var errors *multierror.Error
+	var overlays []*ast.ObjectItem
+	var overlayLists []*ast.ObjectList
+
+	for _, list := range lists {
+		var items []*ast.ObjectItem
+		for _, item := range list.Items {
+			if !isEnvironment(item) {
+				items = append(items, item)
+				continue
+			}
+
+			envName, err := environmentName(item)
+			if err != nil {
+				errors = multierror.Append(errors, err)
+				continue
+			}
+
+			if envName == name {
+				overlays = append(overlays, item)
+				overlayLists = append(overlayLists, list)
+			}
+		}
+		list.Items = items
+	}
+
+	if errors != nil {
+		return errors
+	}
+
+	if name != "" && len(overlays) == 0 {
+		return fmt.Errorf("environment %q is not defined", name)
+	}
+
+	for i, overlay := range overlays {
+		ot, ok := overlay.Val.(*ast.ObjectType)
+		if !ok {
+			errors = multierror.Append(errors, &hclerror.PosError{
+				Pos: overlay.Pos(),
+				Err: fmt.Errorf("environment %q is not an object", name),
+			})
+			continue
+		}
+
+		for _, item := range ot.List.Items {
+			if dst := findItem(lists, item.Keys); dst != nil {
+				mergeItem(dst, item)
+			} else {
+				overlayLists[i].Add(item)
+			}
+		}
+	}
+
+	if errors != nil {
+		return errors
+	}
+
+	return nil
+}
+
+func isEnvironment(item *ast.ObjectItem) bool {
+	return len(item.Keys) > 0 && item.Keys[0].Token.Value() == "environment"
+}
+
+func environmentName(item *ast.ObjectItem) (string, error) {
+	if len(item.Keys) != 2 || item.Keys[1].Token.Value() == "" {
+		return "", &hclerror.PosError{
+			Pos: item.Pos(),
+			Err: fmt.Errorf("environment requires a name"),
+		}
+	}
+
+	return item.Keys[1].Token.Value().(string), nil
+}
+
+func findItem(lists []*ast.ObjectList, keys []*ast.ObjectKey) *ast.ObjectItem {
+	for _, list := range lists {
+		for _, item := range list.Items {
+			if keysEqual(item.Keys, keys) {
+				return item
+			}
+		}
+	}
+
+	return nil
+}
+
+func mergeItem(dst, src *ast.ObjectItem) {
+	dstObj, ok := dst.Val.(*ast.ObjectType)
+	srcObj, ok2 := src.Val.(*ast.ObjectType)
+	if !ok || !ok2 {
+		dst.Val = src.Val
+		return
+	}
+
+	for _, item := range srcObj.List.Items {
+		if match := findItem([]*ast.ObjectList{dstObj.List}, item.Keys); match != nil {
+			mergeItem(match, item)
+		} else {
+			dstObj.List.Add(item)
+		}
+	}
+}
+
+func keysEqual(a, b []*ast.ObjectKey) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i].Token.Value() != b[i].Token.Value() {
+			return false
+		}
+	}
+
+	return true
+}
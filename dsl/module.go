@@ -0,0 +1,184 @@
+package dsl
+
+import (
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/hashicorp/hcl/hcl/token"
+	"github.com/kevinswiber/apigee-hcl/dsl/hclerror"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/resetquota"
+	"path/filepath"
+	"regexp"
+)
+
+// moduleNamePattern matches the names a module can be given. The name
+// prefixes the module's policies and endpoints, so it's kept to
+// characters Apigee allows in those names.
+var moduleNamePattern = regexp.MustCompile(`^[A-Za-z0-9_\-]+$`)
+
+// Module represents a module block, which adds the policies and
+// endpoints defined by the HCL files in another directory. Every
+// attribute besides source sets one of the module's variables.
+//
+// Example:
+//
+//	module "cors" {
+//	  source         = "../modules/cors"
+//	  allowed_origin = "https://example.com"
+//	}
+type Module struct {
+	Name string
+
+	// Source is the module's directory. A relative source is resolved
+	// against the directory of the file declaring the module.
+	Source string
+
+	Inputs Inputs
+	Pos    token.Pos
+}
+
+// DecodeModulesHCL converts the module blocks in an HCL ast.ObjectList
+// into Module objects. Variable references in the blocks must already
+// be interpolated.
+func DecodeModulesHCL(list *ast.ObjectList) ([]*Module, error) {
+	var errors *multierror.Error
+	var modules []*Module
+
+	for _, item := range list.Filter("module").Items {
+		m, err := decodeModuleHCL(item)
+		if err != nil {
+			errors = multierror.Append(errors, err)
+			continue
+		}
+		modules = append(modules, m)
+	}
+
+	if errors != nil {
+		return nil, errors
+	}
+
+	return modules, nil
+}
+
+func decodeModuleHCL(item *ast.ObjectItem) (*Module, error) {
+	var errors *multierror.Error
+
+	if len(item.Keys) == 0 || item.Keys[0].Token.Value() == "" {
+		return nil, &hclerror.PosError{
+			Pos: item.Val.Pos(),
+			Err: fmt.Errorf("module requires a name"),
+		}
+	}
+
+	m := Module{
+		Name:   item.Keys[0].Token.Value().(string),
+		Inputs: make(Inputs),
+		Pos:    item.Pos(),
+	}
+
+	if !moduleNamePattern.MatchString(m.Name) {
+		return nil, &hclerror.PosError{
+			Pos: item.Pos(),
+			Err: fmt.Errorf("invalid module name %q, use letters, digits, - and _", m.Name),
+		}
+	}
+
+	ot, ok := item.Val.(*ast.ObjectType)
+	if !ok {
+		return nil, &hclerror.PosError{
+			Pos: item.Pos(),
+			Err: fmt.Errorf("module %q is not an object", m.Name),
+		}
+	}
+
+	for _, attr := range ot.List.Items {
+		lit, ok := attr.Val.(*ast.LiteralType)
+		if len(attr.Keys) != 1 || !ok {
+			errors = multierror.Append(errors, &hclerror.PosError{
+				Pos: attr.Pos(),
+				Err: fmt.Errorf("module inputs must be strings, numbers or booleans"),
+			})
+			continue
+		}
+
+		name := attr.Keys[0].Token.Value().(string)
+		if name != "source" {
+			m.Inputs[name] = lit.Token.Value()
+			continue
+		}
+
+		source, ok := lit.Token.Value().(string)
+		if !ok || source == "" {
+			errors = multierror.Append(errors, &hclerror.PosError{
+				Pos: attr.Pos(),
+				Err: fmt.Errorf("module source must be a directory"),
+			})
+			continue
+		}
+		m.Source = source
+	}
+
+	if m.Source == "" && errors == nil {
+		errors = multierror.Append(errors, &hclerror.PosError{
+			Pos: item.Pos(),
+			Err: fmt.Errorf("module %q requires a source", m.Name),
+		})
+	}
+
+	if errors != nil {
+		return nil, errors
+	}
+
+	if filename := item.Pos().Filename; !filepath.IsAbs(m.Source) && filename != "" {
+		m.Source = filepath.Join(filepath.Dir(filename), m.Source)
+	}
+
+	return &m, nil
+}
+
+// Namespace prefixes the names of the Config's policies and endpoints
+// with prefix and a dot, e.g. cors.add-cors-headers, so that modules
+// can't collide with each other or with the proxy using them. Steps,
+// route rules and reset quota policies that refer to a renamed policy
+// or endpoint are updated; references to anything else are left as-is.
+func (c *Config) Namespace(prefix string) {
+	rename := func(names map[string]bool, name string) string {
+		if names[name] {
+			return prefix + "." + name
+		}
+		return name
+	}
+
+	policies := make(map[string]bool)
+	for _, p := range c.Policies {
+		policies[p.Name()] = true
+	}
+
+	targetEndpoints := make(map[string]bool)
+	for _, e := range c.TargetEndpoints {
+		targetEndpoints[e.Name] = true
+	}
+
+	for _, s := range c.Steps() {
+		s.Name = rename(policies, s.Name)
+	}
+
+	for _, e := range c.ProxyEndpoints {
+		e.Name = prefix + "." + e.Name
+		for _, r := range e.RouteRules {
+			r.TargetEndpoint = rename(targetEndpoints, r.TargetEndpoint)
+		}
+	}
+
+	for _, e := range c.TargetEndpoints {
+		e.Name = prefix + "." + e.Name
+	}
+
+	for _, p := range c.Policies {
+		p.SetName(prefix + "." + p.Name())
+
+		if r, ok := p.(*resetquota.ResetQuota); ok && r.Quota != nil {
+			r.Quota.Name = rename(policies, r.Quota.Name)
+		}
+	}
+}
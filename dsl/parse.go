@@ -0,0 +1,52 @@
+package dsl
+
+import (
+	"fmt"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	hclParser "github.com/hashicorp/hcl/hcl/parser"
+	"github.com/kevinswiber/apigee-hcl/dsl/hclerror"
+)
+
+// ParseHCL parses HCL source into an ast.ObjectList. The filename is
+// recorded in the position of every node, so errors found while decoding
+// and validating point back at the source file.
+func ParseHCL(filename string, src []byte) (*ast.ObjectList, error) {
+	hclRoot, err := hcl.Parse(string(src))
+	if err != nil {
+		if e, ok := err.(*hclParser.PosError); ok {
+			e2 := &hclerror.PosError{
+				Pos: e.Pos,
+				Err: e.Err,
+			}
+			e2.Pos.Filename = filename
+			return nil, e2
+		}
+		return nil, err
+	}
+
+	list, ok := hclRoot.Node.(*ast.ObjectList)
+	if !ok {
+		return nil, fmt.Errorf("%s: file doesn't contain root object", filename)
+	}
+
+	ast.Walk(list, func(n ast.Node) (ast.Node, bool) {
+		switch n := n.(type) {
+		case *ast.ObjectKey:
+			n.Token.Pos.Filename = filename
+		case *ast.LiteralType:
+			n.Token.Pos.Filename = filename
+		case *ast.ListType:
+			n.Lbrack.Filename = filename
+			n.Rbrack.Filename = filename
+		case *ast.ObjectType:
+			n.Lbrace.Filename = filename
+			n.Rbrace.Filename = filename
+		case *ast.ObjectItem:
+			n.Assign.Filename = filename
+		}
+		return n, true
+	})
+
+	return list, nil
+}
@@ -0,0 +1,191 @@
+package accesscontrol
+
+import (
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/kevinswiber/apigee-hcl/dsl/hclerror"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/policy"
+)
+
+// AccessControl represents an <AccessControl/> element.
+//
+// Documentation: http://docs.apigee.com/api-services/reference/access-control-policy
+type AccessControl struct {
+	XMLName         string `xml:"AccessControl" hcl:"-"`
+	policy.Policy   `hcl:",squash"`
+	DisplayName     string   `xml:",omitempty" hcl:"display_name"`
+	IPRules         *ipRules `hcl:"ip_rules"`
+	ValidateBasedOn string   `xml:",omitempty" hcl:"validate_based_on"`
+}
+
+type ipRules struct {
+	XMLName           string       `xml:"IPRules" hcl:"-"`
+	NoRuleMatchAction string       `xml:"noRuleMatchAction,attr,omitempty" hcl:"no_rule_match_action"`
+	MatchRules        []*matchRule `xml:"MatchRule" hcl:"match_rule"`
+}
+
+type matchRule struct {
+	XMLName         string           `xml:"MatchRule" hcl:"-"`
+	Action          string           `xml:"action,attr" hcl:"action"`
+	SourceAddresses []*sourceAddress `xml:"SourceAddress" hcl:"source_address"`
+}
+
+type sourceAddress struct {
+	XMLName string `xml:"SourceAddress" hcl:"-"`
+	Mask    int    `xml:"mask,attr,omitempty" hcl:"mask"`
+	Value   string `xml:",chardata" hcl:"value"`
+}
+
+// DecodeHCL converts an HCL ast.ObjectItem into an AccessControl object.
+func DecodeHCL(item *ast.ObjectItem) (interface{}, error) {
+	var errors *multierror.Error
+	var p AccessControl
+
+	if err := policy.DecodeHCL(item, &p.Policy); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	var listVal *ast.ObjectList
+	if ot, ok := item.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("access control policy not an object"),
+		}
+		return nil, &newError
+	}
+
+	var attrs struct {
+		DisplayName     string `hcl:"display_name"`
+		ValidateBasedOn string `hcl:"validate_based_on"`
+	}
+	if err := hcl.DecodeObject(&attrs, item.Val); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	p.DisplayName = attrs.DisplayName
+	p.ValidateBasedOn = attrs.ValidateBasedOn
+
+	if ipRulesList := listVal.Filter("ip_rules"); len(ipRulesList.Items) > 0 {
+		rules, err := decodeIPRulesHCL(ipRulesList.Items[0])
+		if err != nil {
+			errors = multierror.Append(errors, err)
+		} else {
+			p.IPRules = rules
+		}
+	} else {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("access control requires ip_rules"),
+		}
+		errors = multierror.Append(errors, &newError)
+	}
+
+	if errors != nil {
+		return nil, errors
+	}
+
+	return &p, nil
+}
+
+func decodeIPRulesHCL(item *ast.ObjectItem) (*ipRules, error) {
+	var rules ipRules
+
+	var listVal *ast.ObjectList
+	if ot, ok := item.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("ip_rules not an object"),
+		}
+		return nil, &newError
+	}
+
+	var attrs struct {
+		NoRuleMatchAction string `hcl:"no_rule_match_action"`
+	}
+	if err := hcl.DecodeObject(&attrs, item.Val); err != nil {
+		return nil, err
+	}
+	rules.NoRuleMatchAction = attrs.NoRuleMatchAction
+
+	for _, ruleItem := range listVal.Filter("match_rule").Items {
+		rule, err := decodeMatchRuleHCL(ruleItem)
+		if err != nil {
+			return nil, err
+		}
+		rules.MatchRules = append(rules.MatchRules, rule)
+	}
+
+	if len(rules.MatchRules) == 0 {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("ip_rules requires at least one match_rule"),
+		}
+		return nil, &newError
+	}
+
+	return &rules, nil
+}
+
+func decodeMatchRuleHCL(item *ast.ObjectItem) (*matchRule, error) {
+	var rule matchRule
+
+	var listVal *ast.ObjectList
+	if ot, ok := item.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("match_rule not an object"),
+		}
+		return nil, &newError
+	}
+
+	var attrs struct {
+		Action string `hcl:"action"`
+	}
+	if err := hcl.DecodeObject(&attrs, item.Val); err != nil {
+		return nil, err
+	}
+
+	if attrs.Action != "ALLOW" && attrs.Action != "DENY" {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("match_rule action must be ALLOW or DENY, got %q", attrs.Action),
+		}
+		return nil, &newError
+	}
+	rule.Action = attrs.Action
+
+	for _, addressItem := range listVal.Filter("source_address").Items {
+		var address sourceAddress
+		if err := hcl.DecodeObject(&address, addressItem.Val); err != nil {
+			return nil, err
+		}
+		rule.SourceAddresses = append(rule.SourceAddresses, &address)
+	}
+
+	if len(rule.SourceAddresses) == 0 {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("match_rule requires at least one source_address"),
+		}
+		return nil, &newError
+	}
+
+	return &rule, nil
+}
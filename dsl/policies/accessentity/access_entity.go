@@ -0,0 +1,106 @@
+package accessentity
+
+import (
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/kevinswiber/apigee-hcl/dsl/hclerror"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/policy"
+)
+
+// AccessEntity represents an <AccessEntity/> element.
+//
+// Documentation: http://docs.apigee.com/api-services/reference/access-entity-policy
+type AccessEntity struct {
+	XMLName             string `xml:"AccessEntity" hcl:"-"`
+	policy.Policy       `hcl:",squash"`
+	DisplayName         string      `xml:",omitempty" hcl:"display_name"`
+	EntityType          *entityType `hcl:"entity_type"`
+	EntityIdentifier    *identifier `hcl:"entity_identifier"`
+	SecondaryIdentifier *identifier `xml:",omitempty" hcl:"secondary_identifier"`
+	OutputFormat        string      `xml:",omitempty" hcl:"output_format"`
+}
+
+type entityType struct {
+	XMLName string `xml:"EntityType" hcl:"-"`
+	Value   string `xml:"value,attr" hcl:"value"`
+}
+
+type identifier struct {
+	Ref  string `xml:"ref,attr" hcl:"ref"`
+	Type string `xml:"type,attr,omitempty" hcl:"type"`
+}
+
+var entityTypes = []string{
+	"apiproduct",
+	"app",
+	"company",
+	"companydeveloper",
+	"consumerkey",
+	"developer",
+}
+
+// DecodeHCL converts an HCL ast.ObjectItem into an AccessEntity object.
+func DecodeHCL(item *ast.ObjectItem) (interface{}, error) {
+	var errors *multierror.Error
+	var p AccessEntity
+
+	if err := policy.DecodeHCL(item, &p.Policy); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	if _, ok := item.Val.(*ast.ObjectType); !ok {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("access entity policy not an object"),
+		}
+		return nil, &newError
+	}
+
+	if err := hcl.DecodeObject(&p, item.Val.(*ast.ObjectType)); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	if p.EntityType == nil || !validEntityType(p.EntityType.Value) {
+		value := ""
+		if p.EntityType != nil {
+			value = p.EntityType.Value
+		}
+
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("access entity entity_type must be one of %v, got %q", entityTypes, value),
+		}
+		errors = multierror.Append(errors, &newError)
+	}
+
+	if p.EntityIdentifier == nil {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("access entity requires entity_identifier"),
+		}
+		errors = multierror.Append(errors, &newError)
+	}
+
+	if errors != nil {
+		return nil, errors
+	}
+
+	return &p, nil
+}
+
+func validEntityType(entityType string) bool {
+	for _, t := range entityTypes {
+		if t == entityType {
+			return true
+		}
+	}
+
+	return false
+}
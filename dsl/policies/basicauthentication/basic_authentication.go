@@ -0,0 +1,101 @@
+package basicauthentication
+
+import (
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/kevinswiber/apigee-hcl/dsl/hclerror"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/policy"
+)
+
+// BasicAuthentication represents a <BasicAuthentication/> element.
+//
+// Documentation: http://docs.apigee.com/api-services/reference/basic-authentication-policy
+type BasicAuthentication struct {
+	XMLName                   string `xml:"BasicAuthentication" hcl:"-"`
+	policy.Policy             `hcl:",squash"`
+	DisplayName               string     `xml:",omitempty" hcl:"display_name"`
+	Operation                 string     `hcl:"operation"`
+	IgnoreUnresolvedVariables bool       `xml:",omitempty" hcl:"ignore_unresolved_variables"`
+	User                      *reference `xml:",omitempty" hcl:"user"`
+	Password                  *reference `xml:",omitempty" hcl:"password"`
+	AssignTo                  *assignTo  `xml:",omitempty" hcl:"assign_to"`
+	Source                    string     `xml:",omitempty" hcl:"source"`
+}
+
+type reference struct {
+	Ref string `xml:"ref,attr" hcl:"ref"`
+}
+
+type assignTo struct {
+	XMLName   string `xml:"AssignTo" hcl:"-"`
+	CreateNew bool   `xml:"createNew,attr" hcl:"create_new"`
+	Value     string `xml:",chardata" hcl:"value"`
+}
+
+// DecodeHCL converts an HCL ast.ObjectItem into a BasicAuthentication object.
+func DecodeHCL(item *ast.ObjectItem) (interface{}, error) {
+	var errors *multierror.Error
+	var p BasicAuthentication
+
+	if err := policy.DecodeHCL(item, &p.Policy); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	if _, ok := item.Val.(*ast.ObjectType); !ok {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("basic authentication policy not an object"),
+		}
+		return nil, &newError
+	}
+
+	if err := hcl.DecodeObject(&p, item.Val.(*ast.ObjectType)); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	var missing []string
+	if p.User == nil {
+		missing = append(missing, "user")
+	}
+	if p.Password == nil {
+		missing = append(missing, "password")
+	}
+
+	switch p.Operation {
+	case "Encode":
+		if p.AssignTo == nil {
+			missing = append(missing, "assign_to")
+		}
+	case "Decode":
+		if p.Source == "" {
+			missing = append(missing, "source")
+		}
+	default:
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("basic authentication operation must be Encode or Decode, got %q", p.Operation),
+		}
+		errors = multierror.Append(errors, &newError)
+	}
+
+	for _, key := range missing {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("basic authentication requires %s", key),
+		}
+		errors = multierror.Append(errors, &newError)
+	}
+
+	if errors != nil {
+		return nil, errors
+	}
+
+	return &p, nil
+}
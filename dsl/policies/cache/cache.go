@@ -0,0 +1,86 @@
+// Package cache contains the elements shared by the cache policies.
+package cache
+
+import (
+	"fmt"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/kevinswiber/apigee-hcl/dsl/hclerror"
+)
+
+// CacheKey represents a <CacheKey/> element.
+type CacheKey struct {
+	XMLName     string         `xml:"CacheKey" hcl:"-"`
+	Prefix      string         `xml:",omitempty" hcl:"prefix"`
+	KeyFragment []*KeyFragment `xml:",omitempty" hcl:"key_fragment"`
+}
+
+// KeyFragment represents a <KeyFragment/> element.
+type KeyFragment struct {
+	XMLName string `xml:"KeyFragment" hcl:"-"`
+	Ref     string `xml:"ref,attr,omitempty" hcl:"ref"`
+	Value   string `xml:",chardata" hcl:"value"`
+}
+
+// ExpirySettings represents an <ExpirySettings/> element.
+type ExpirySettings struct {
+	XMLName      string        `xml:"ExpirySettings" hcl:"-"`
+	TimeOfDay    *TimeOfDay    `xml:",omitempty" hcl:"time_of_day"`
+	TimeoutInSec *TimeoutInSec `xml:",omitempty" hcl:"timeout_in_sec"`
+	ExpiryDate   *ExpiryDate   `xml:",omitempty" hcl:"expiry_date"`
+}
+
+// TimeOfDay represents a <TimeOfDay/> element.
+type TimeOfDay struct {
+	XMLName string `xml:"TimeOfDay" hcl:"-"`
+	Ref     string `xml:"ref,attr,omitempty" hcl:"ref"`
+	Value   string `xml:",chardata" hcl:"value"`
+}
+
+// TimeoutInSec represents a <TimeoutInSec/> element.
+type TimeoutInSec struct {
+	XMLName string `xml:"TimeoutInSec" hcl:"-"`
+	Ref     string `xml:"ref,attr,omitempty" hcl:"ref"`
+	Value   string `xml:",chardata" hcl:"value"`
+}
+
+// ExpiryDate represents an <ExpiryDate/> element.
+type ExpiryDate struct {
+	XMLName string `xml:"ExpiryDate" hcl:"-"`
+	Ref     string `xml:"ref,attr,omitempty" hcl:"ref"`
+	Value   string `xml:",chardata" hcl:"value"`
+}
+
+// DecodeCacheKeyHCL converts an HCL ast.ObjectItem into a CacheKey object.
+// Each key_fragment block becomes a single KeyFragment, even when it sets
+// both ref and value.
+func DecodeCacheKeyHCL(item *ast.ObjectItem) (*CacheKey, error) {
+	var k CacheKey
+
+	var listVal *ast.ObjectList
+	if ot, ok := item.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("cache_key not an object"),
+		}
+		return nil, &newError
+	}
+
+	if err := hcl.DecodeObject(&k, item.Val.(*ast.ObjectType)); err != nil {
+		return nil, err
+	}
+
+	k.KeyFragment = nil
+	for _, fragmentItem := range listVal.Filter("key_fragment").Items {
+		var f KeyFragment
+		if err := hcl.DecodeObject(&f, fragmentItem.Val); err != nil {
+			return nil, err
+		}
+		k.KeyFragment = append(k.KeyFragment, &f)
+	}
+
+	return &k, nil
+}
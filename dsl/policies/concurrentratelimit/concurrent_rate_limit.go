@@ -0,0 +1,99 @@
+package concurrentratelimit
+
+import (
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/kevinswiber/apigee-hcl/dsl/hclerror"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/policy"
+)
+
+// ConcurrentRateLimit represents a <ConcurrentRatelimit/> element.
+//
+// Documentation: http://docs.apigee.com/api-services/reference/concurrent-rate-limit-policy
+type ConcurrentRateLimit struct {
+	XMLName          string `xml:"ConcurrentRatelimit" hcl:"-"`
+	policy.Policy    `hcl:",squash"`
+	DisplayName      string            `xml:",omitempty" hcl:"display_name"`
+	AllowConnections *allowConnections `hcl:"allow_connections"`
+	Distributed      bool              `xml:",omitempty" hcl:"distributed"`
+	StrictOnTTL      bool              `xml:"StrictOnTtl,omitempty" hcl:"strict_on_ttl"`
+	TargetIdentifier *targetIdentifier `hcl:"target_identifier"`
+}
+
+type allowConnections struct {
+	XMLName string `xml:"AllowConnections" hcl:"-"`
+	Count   int    `xml:"count,attr" hcl:"count"`
+	TTL     int    `xml:"ttl,attr,omitempty" hcl:"ttl"`
+}
+
+type targetIdentifier struct {
+	XMLName string `xml:"TargetIdentifier" hcl:"-"`
+	Name    string `xml:"name,attr" hcl:"-"`
+	Ref     string `xml:"ref,attr,omitempty" hcl:"ref"`
+}
+
+// DecodeHCL converts an HCL ast.ObjectItem into a ConcurrentRateLimit object.
+func DecodeHCL(item *ast.ObjectItem) (interface{}, error) {
+	var errors *multierror.Error
+	var p ConcurrentRateLimit
+
+	if err := policy.DecodeHCL(item, &p.Policy); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	var listVal *ast.ObjectList
+	if ot, ok := item.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("concurrent rate limit policy not an object"),
+		}
+		return nil, &newError
+	}
+
+	if err := hcl.DecodeObject(&p, item.Val.(*ast.ObjectType)); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	if p.AllowConnections == nil || p.AllowConnections.Count <= 0 {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("concurrent rate limit requires allow_connections with a count"),
+		}
+		errors = multierror.Append(errors, &newError)
+	}
+
+	if targetList := listVal.Filter("target_identifier"); len(targetList.Items) > 0 {
+		targetItem := targetList.Items[0]
+		if len(targetItem.Keys) == 0 || targetItem.Keys[0].Token.Value().(string) == "" {
+			pos := targetItem.Val.Pos()
+			newError := hclerror.PosError{
+				Pos: pos,
+				Err: fmt.Errorf("target_identifier requires a name"),
+			}
+			errors = multierror.Append(errors, &newError)
+		} else {
+			p.TargetIdentifier.Name = targetItem.Keys[0].Token.Value().(string)
+		}
+	} else {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("concurrent rate limit requires target_identifier"),
+		}
+		errors = multierror.Append(errors, &newError)
+	}
+
+	if errors != nil {
+		return nil, errors
+	}
+
+	return &p, nil
+}
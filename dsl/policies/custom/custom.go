@@ -0,0 +1,379 @@
+package custom
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/kevinswiber/apigee-hcl/dsl/hclerror"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/policy"
+	"strconv"
+	"strings"
+)
+
+// Custom represents a policy of any type, written as a root element and
+// a generic body. It covers policies that have no dedicated HCL type.
+//
+// In the body, each key becomes a child element. Nested blocks become
+// nested elements, lists repeat the element, keys starting with @ become
+// attributes and #text sets an element's text. Attributes at the top of
+// the body are set on the root element.
+type Custom struct {
+	policy.Policy `hcl:",squash"`
+	Element       string     `hcl:"element"`
+	Attrs         []xml.Attr `hcl:"-"`
+	Body          []*Node    `hcl:"-"`
+}
+
+// Node represents an element of a Custom policy's body.
+type Node struct {
+	Name     string
+	Attrs    []xml.Attr
+	Text     string
+	Children []*Node
+}
+
+// MarshalXML writes the policy with its element as the root.
+func (c *Custom) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start = xml.StartElement{Name: xml.Name{Local: c.Element}}
+	if c.Name() != "" {
+		start.Attr = append(start.Attr, attr("name", c.Name()))
+	}
+	start.Attr = append(start.Attr, attr("enabled", strconv.FormatBool(c.Enabled)))
+	if c.ContinueOnError {
+		start.Attr = append(start.Attr, attr("continueOnError", "true"))
+	}
+	if c.Async {
+		start.Attr = append(start.Attr, attr("async", "true"))
+	}
+	start.Attr = append(start.Attr, c.Attrs...)
+
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+
+	for _, n := range c.Body {
+		if err := e.Encode(n); err != nil {
+			return err
+		}
+	}
+
+	return e.EncodeToken(start.End())
+}
+
+// MarshalXML writes the node and its children.
+func (n *Node) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start = xml.StartElement{Name: xml.Name{Local: n.Name}, Attr: n.Attrs}
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+
+	if n.Text != "" {
+		if err := e.EncodeToken(xml.CharData(n.Text)); err != nil {
+			return err
+		}
+	}
+
+	for _, c := range n.Children {
+		if err := e.Encode(c); err != nil {
+			return err
+		}
+	}
+
+	return e.EncodeToken(start.End())
+}
+
+// DecodeXML converts a policy document of any type into a Custom object.
+// Like Apigee, it treats a missing enabled attribute as enabled.
+//
+// Names are read as written, so namespace prefixes are kept. Whitespace
+// between child elements is dropped.
+func DecodeXML(data []byte) (*Custom, error) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+
+	for {
+		t, err := d.RawToken()
+		if err != nil {
+			return nil, err
+		}
+
+		start, ok := t.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		var c Custom
+		c.Element = qualifiedName(start.Name)
+		c.Enabled = true
+
+		for _, a := range start.Attr {
+			var err error
+			switch name := qualifiedName(a.Name); name {
+			case "name":
+				c.SetName(a.Value)
+			case "enabled":
+				c.Enabled, err = strconv.ParseBool(a.Value)
+			case "continueOnError":
+				c.ContinueOnError, err = strconv.ParseBool(a.Value)
+			case "async":
+				c.Async, err = strconv.ParseBool(a.Value)
+			default:
+				c.Attrs = append(c.Attrs, attr(name, a.Value))
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%s attribute %s: %v", c.Element, qualifiedName(a.Name), err)
+			}
+		}
+
+		_, children, err := decodeChildrenXML(d)
+		if err != nil {
+			return nil, err
+		}
+		c.Body = children
+
+		return &c, nil
+	}
+}
+
+func decodeChildrenXML(d *xml.Decoder) (string, []*Node, error) {
+	var text string
+	var children []*Node
+
+	for {
+		t, err := d.RawToken()
+		if err != nil {
+			return "", nil, err
+		}
+
+		switch t := t.(type) {
+		case xml.StartElement:
+			n := Node{Name: qualifiedName(t.Name)}
+			for _, a := range t.Attr {
+				n.Attrs = append(n.Attrs, attr(qualifiedName(a.Name), a.Value))
+			}
+
+			childText, childChildren, err := decodeChildrenXML(d)
+			if err != nil {
+				return "", nil, err
+			}
+
+			if len(childChildren) == 0 || strings.TrimSpace(childText) != "" {
+				n.Text = childText
+			}
+			n.Children = childChildren
+
+			children = append(children, &n)
+		case xml.CharData:
+			text += string(t)
+		case xml.EndElement:
+			return text, children, nil
+		}
+	}
+}
+
+func attr(name, value string) xml.Attr {
+	return xml.Attr{Name: xml.Name{Local: name}, Value: value}
+}
+
+// qualifiedName returns a raw name with its prefix, e.g. xmlns:soap.
+func qualifiedName(name xml.Name) string {
+	if name.Space != "" {
+		return name.Space + ":" + name.Local
+	}
+
+	return name.Local
+}
+
+// DecodeHCL converts an HCL ast.ObjectItem into a Custom object.
+func DecodeHCL(item *ast.ObjectItem) (interface{}, error) {
+	var errors *multierror.Error
+	var p Custom
+
+	if err := policy.DecodeHCL(item, &p.Policy); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	var listVal *ast.ObjectList
+	if ot, ok := item.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("custom policy not an object"),
+		}
+		return nil, &newError
+	}
+
+	var attrs struct {
+		Element string `hcl:"element"`
+	}
+	if err := hcl.DecodeObject(&attrs, item.Val); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+	p.Element = attrs.Element
+
+	if !validName(p.Element) {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("custom policy requires an element name, got %q", p.Element),
+		}
+		errors = multierror.Append(errors, &newError)
+	}
+
+	if bodyList := listVal.Filter("body"); len(bodyList.Items) > 0 {
+		body, err := decodeBodyHCL(bodyList.Items[0])
+		if err != nil {
+			errors = multierror.Append(errors, err)
+		} else {
+			p.Attrs = body.Attrs
+			p.Body = body.Children
+		}
+
+		for _, a := range p.Attrs {
+			switch a.Name.Local {
+			case "name", "enabled", "continueOnError", "async":
+				pos := bodyList.Items[0].Val.Pos()
+				newError := hclerror.PosError{
+					Pos: pos,
+					Err: fmt.Errorf("custom policy body can't set the %s attribute, set it on the policy", a.Name.Local),
+				}
+				errors = multierror.Append(errors, &newError)
+			}
+		}
+	}
+
+	if errors != nil {
+		return nil, errors
+	}
+
+	return &p, nil
+}
+
+// decodeBodyHCL converts a block into a Node, keeping the order of its
+// keys.
+func decodeBodyHCL(item *ast.ObjectItem) (*Node, error) {
+	var n Node
+
+	ot, ok := item.Val.(*ast.ObjectType)
+	if !ok {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("body not an object"),
+		}
+		return nil, &newError
+	}
+
+	for _, child := range ot.List.Items {
+		if len(child.Keys) != 1 {
+			pos := child.Val.Pos()
+			newError := hclerror.PosError{
+				Pos: pos,
+				Err: fmt.Errorf("custom policy elements can't have labels, use \"@name\" for attributes"),
+			}
+			return nil, &newError
+		}
+
+		key := child.Keys[0].Token.Value().(string)
+		switch {
+		case key == "#text":
+			text, err := literal(child.Val)
+			if err != nil {
+				return nil, err
+			}
+			n.Text = text
+		case strings.HasPrefix(key, "@"):
+			if !validName(strings.TrimPrefix(key, "@")) {
+				pos := child.Val.Pos()
+				newError := hclerror.PosError{
+					Pos: pos,
+					Err: fmt.Errorf("invalid attribute name %q", key),
+				}
+				return nil, &newError
+			}
+
+			value, err := literal(child.Val)
+			if err != nil {
+				return nil, err
+			}
+			n.Attrs = append(n.Attrs, attr(strings.TrimPrefix(key, "@"), value))
+		default:
+			if !validName(key) {
+				pos := child.Val.Pos()
+				newError := hclerror.PosError{
+					Pos: pos,
+					Err: fmt.Errorf("invalid element name %q", key),
+				}
+				return nil, &newError
+			}
+
+			children, err := decodeNodesHCL(key, child.Val)
+			if err != nil {
+				return nil, err
+			}
+			n.Children = append(n.Children, children...)
+		}
+	}
+
+	return &n, nil
+}
+
+// decodeNodesHCL converts the value of a body key into the elements it
+// stands for: one for a literal or a block, one per entry for a list.
+func decodeNodesHCL(name string, val ast.Node) ([]*Node, error) {
+	switch v := val.(type) {
+	case *ast.ObjectType:
+		n, err := decodeBodyHCL(&ast.ObjectItem{Val: v})
+		if err != nil {
+			return nil, err
+		}
+		n.Name = name
+		return []*Node{n}, nil
+	case *ast.ListType:
+		var nodes []*Node
+		for _, listItem := range v.List {
+			ns, err := decodeNodesHCL(name, listItem)
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, ns...)
+		}
+		return nodes, nil
+	default:
+		text, err := literal(val)
+		if err != nil {
+			return nil, err
+		}
+		return []*Node{{Name: name, Text: text}}, nil
+	}
+}
+
+func literal(val ast.Node) (string, error) {
+	lit, ok := val.(*ast.LiteralType)
+	if !ok {
+		newError := hclerror.PosError{
+			Pos: val.Pos(),
+			Err: fmt.Errorf("expected a string, number or bool"),
+		}
+		return "", &newError
+	}
+
+	return fmt.Sprint(lit.Token.Value()), nil
+}
+
+// validName reports whether name can be used as an XML element or
+// attribute name.
+func validName(name string) bool {
+	if name == "" || strings.ContainsAny(name, " \t\n<>&\"'=/") {
+		return false
+	}
+
+	c := name[0]
+	return c == '_' || c == ':' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
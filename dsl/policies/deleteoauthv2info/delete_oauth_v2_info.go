@@ -0,0 +1,66 @@
+package deleteoauthv2info
+
+import (
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/kevinswiber/apigee-hcl/dsl/hclerror"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/policy"
+)
+
+// DeleteOAuthV2Info represents a <DeleteOAuthV2Info/> element.
+//
+// Documentation: http://docs.apigee.com/api-services/reference/delete-oauth-v2-info-policy
+type DeleteOAuthV2Info struct {
+	XMLName           string `xml:"DeleteOAuthV2Info" hcl:"-"`
+	policy.Policy     `hcl:",squash"`
+	DisplayName       string     `xml:",omitempty" hcl:"display_name"`
+	AccessToken       *reference `xml:",omitempty" hcl:"access_token"`
+	AuthorizationCode *reference `xml:",omitempty" hcl:"authorization_code"`
+}
+
+type reference struct {
+	Ref string `xml:"ref,attr" hcl:"ref"`
+}
+
+// DecodeHCL converts an HCL ast.ObjectItem into a DeleteOAuthV2Info object.
+func DecodeHCL(item *ast.ObjectItem) (interface{}, error) {
+	var errors *multierror.Error
+	var p DeleteOAuthV2Info
+
+	if err := policy.DecodeHCL(item, &p.Policy); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	if _, ok := item.Val.(*ast.ObjectType); !ok {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("delete oauth v2 info policy not an object"),
+		}
+		return nil, &newError
+	}
+
+	if err := hcl.DecodeObject(&p, item.Val.(*ast.ObjectType)); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	if (p.AccessToken == nil) == (p.AuthorizationCode == nil) {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("delete oauth v2 info requires exactly one of access_token " +
+				"or authorization_code"),
+		}
+		errors = multierror.Append(errors, &newError)
+	}
+
+	if errors != nil {
+		return nil, errors
+	}
+
+	return &p, nil
+}
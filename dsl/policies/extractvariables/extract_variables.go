@@ -15,17 +15,17 @@ import (
 type ExtractVariables struct {
 	XMLName                   string `xml:"ExtractVariables" hcl:"-"`
 	policy.Policy             `hcl:",squash"`
-	DisplayName               string          `xml:",omitempty" hcl:"display_name"`
-	Source                    *evSource       `xml:",omitempty" hcl:"source"`
-	VariablePrefix            string          `xml:",omitempty" hcl:"variable_prefix"`
-	IgnoreUnresolvedVariables bool            `xml:",omitempty" hcl:"ignore_unresolved_variables"`
-	URIPaths                  []*evURIPath    `xml:"URIPath,omitempty" hcl:"uri_path"`
-	QueryParams               []*evQueryParam `xml:"QueryParam,omitempty" hcl:"query_param"`
-	Headers                   []*evHeader     `xml:"Header,omitempty" hcl:"header"`
-	FormParams                []*evFormParam  `xml:"FormParam,omitempty" hcl:"form_param"`
-	Variables                 []*evVariable   `xml:"Variable,omitempty" hcl:"variable"`
-	JSONPayload               *evJSONPayload  `xml:",omitempty" hcl:"json_payload"`
-	XMLPayload                *evXMLPayload   `xml:",omitempty" hcl:"xml_payload"`
+	DisplayName               string         `xml:",omitempty" hcl:"display_name"`
+	Source                    *evSource      `xml:",omitempty" hcl:"source"`
+	VariablePrefix            string         `xml:",omitempty" hcl:"variable_prefix"`
+	IgnoreUnresolvedVariables bool           `xml:",omitempty" hcl:"ignore_unresolved_variables"`
+	URIPaths                  []*URIPath     `xml:"URIPath,omitempty" hcl:"uri_path"`
+	QueryParams               []*QueryParam  `xml:"QueryParam,omitempty" hcl:"query_param"`
+	Headers                   []*Header      `xml:"Header,omitempty" hcl:"header"`
+	FormParams                []*FormParam   `xml:"FormParam,omitempty" hcl:"form_param"`
+	Variables                 []*Variable    `xml:"Variable,omitempty" hcl:"variable"`
+	JSONPayload               *evJSONPayload `xml:",omitempty" hcl:"json_payload"`
+	XMLPayload                *evXMLPayload  `xml:",omitempty" hcl:"xml_payload"`
 }
 
 type evSource struct {
@@ -34,33 +34,38 @@ type evSource struct {
 	Value        string `xml:",chardata" hcl:"value"`
 }
 
-type evURIPath struct {
-	XMLName  string       `xml:"URIPath" hcl:"-"`
-	Patterns []*evPattern `xml:"Pattern" hcl:"pattern"`
+// URIPath represents a <URIPath/> element.
+type URIPath struct {
+	XMLName  string     `xml:"URIPath" hcl:"-"`
+	Patterns []*Pattern `xml:"Pattern" hcl:"pattern"`
 }
 
-type evQueryParam struct {
-	XMLName  string       `xml:"QueryParam" hcl:"-"`
-	Name     string       `xml:"name,attr" hcl:"-"`
-	Patterns []*evPattern `xml:"Pattern" hcl:"pattern"`
+// QueryParam represents a <QueryParam/> element.
+type QueryParam struct {
+	XMLName  string     `xml:"QueryParam" hcl:"-"`
+	Name     string     `xml:"name,attr" hcl:"-"`
+	Patterns []*Pattern `xml:"Pattern" hcl:"pattern"`
 }
 
-type evHeader struct {
-	XMLName  string       `xml:"Header" hcl:"-"`
-	Name     string       `xml:"name,attr" hcl:"-"`
-	Patterns []*evPattern `xml:"Pattern" hcl:"pattern"`
+// Header represents a <Header/> element.
+type Header struct {
+	XMLName  string     `xml:"Header" hcl:"-"`
+	Name     string     `xml:"name,attr" hcl:"-"`
+	Patterns []*Pattern `xml:"Pattern" hcl:"pattern"`
 }
 
-type evFormParam struct {
-	XMLName  string       `xml:"FormParam" hcl:"-"`
-	Name     string       `xml:"name,attr" hcl:"-"`
-	Patterns []*evPattern `xml:"Pattern" hcl:"pattern"`
+// FormParam represents a <FormParam/> element.
+type FormParam struct {
+	XMLName  string     `xml:"FormParam" hcl:"-"`
+	Name     string     `xml:"name,attr" hcl:"-"`
+	Patterns []*Pattern `xml:"Pattern" hcl:"pattern"`
 }
 
-type evVariable struct {
-	XMLName  string       `xml:"Variable" hcl:"-"`
-	Name     string       `xml:"name,attr" hcl:"-"`
-	Patterns []*evPattern `xml:"Pattern" hcl:"pattern"`
+// Variable represents a <Variable/> element.
+type Variable struct {
+	XMLName  string     `xml:"Variable" hcl:"-"`
+	Name     string     `xml:"name,attr" hcl:"-"`
+	Patterns []*Pattern `xml:"Pattern" hcl:"pattern"`
 }
 
 type evJSONPayload struct {
@@ -76,13 +81,14 @@ type evJSONPayloadVariable struct {
 }
 
 type evXMLPayload struct {
-	XMLName               string                   `xml:"XMLPayload" hcl:"-"`
-	StopPayloadProcessing bool                     `xml:"stopPayloadProcessing,attr,omitempty" hcl:"stop_payload_processing"`
-	Namespaces            []*evXMLPayloadNamespace `xml:"Namespaces>Namespace,omitempty" hcl:"namespace"`
-	Variables             []*evXMLPayloadVariable  `xml:"Variable" hcl:"variable"`
+	XMLName               string                  `xml:"XMLPayload" hcl:"-"`
+	StopPayloadProcessing bool                    `xml:"stopPayloadProcessing,attr,omitempty" hcl:"stop_payload_processing"`
+	Namespaces            []*Namespace            `xml:"Namespaces>Namespace,omitempty" hcl:"namespace"`
+	Variables             []*evXMLPayloadVariable `xml:"Variable" hcl:"variable"`
 }
 
-type evXMLPayloadNamespace struct {
+// Namespace represents a <Namespace/> element.
+type Namespace struct {
 	Prefix string `xml:"prefix,attr,omitempty" hcl:"-"`
 	Value  string `xml:",chardata" hcl:"value"`
 }
@@ -94,7 +100,8 @@ type evXMLPayloadVariable struct {
 	XPath   string `hcl:"xpath"`
 }
 
-type evPattern struct {
+// Pattern represents a <Pattern/> element.
+type Pattern struct {
 	XMLName    string `xml:"Pattern" hcl:"-"`
 	IgnoreCase bool   `xml:"ignoreCase,attr,omitempty" hcl:"ignore_case"`
 	Value      string `xml:",chardata" hcl:"value"`
@@ -126,7 +133,7 @@ func DecodeHCL(item *ast.ObjectItem) (interface{}, error) {
 	}
 
 	if uriPathList := listVal.Filter("uri_path"); len(uriPathList.Items) > 0 {
-		uriPaths, err := decodeURIPathsHCL(uriPathList.Items)
+		uriPaths, err := DecodeURIPathsHCL(uriPathList.Items)
 		if err != nil {
 			errors = multierror.Append(errors, err)
 		} else {
@@ -135,7 +142,7 @@ func DecodeHCL(item *ast.ObjectItem) (interface{}, error) {
 	}
 
 	if queryParamList := listVal.Filter("query_param"); len(queryParamList.Items) > 0 {
-		queryParams, err := decodeQueryParamsHCL(queryParamList.Items)
+		queryParams, err := DecodeQueryParamsHCL(queryParamList.Items)
 		if err != nil {
 			errors = multierror.Append(errors, err)
 		} else {
@@ -144,7 +151,7 @@ func DecodeHCL(item *ast.ObjectItem) (interface{}, error) {
 	}
 
 	if headerList := listVal.Filter("header"); len(headerList.Items) > 0 {
-		headers, err := decodeHeadersHCL(headerList.Items)
+		headers, err := DecodeHeadersHCL(headerList.Items)
 		if err != nil {
 			errors = multierror.Append(errors, err)
 		} else {
@@ -153,7 +160,7 @@ func DecodeHCL(item *ast.ObjectItem) (interface{}, error) {
 	}
 
 	if formParamList := listVal.Filter("form_param"); len(formParamList.Items) > 0 {
-		formParams, err := decodeFormParamsHCL(formParamList.Items)
+		formParams, err := DecodeFormParamsHCL(formParamList.Items)
 		if err != nil {
 			errors = multierror.Append(errors, err)
 		} else {
@@ -162,7 +169,7 @@ func DecodeHCL(item *ast.ObjectItem) (interface{}, error) {
 	}
 
 	if variableList := listVal.Filter("variable"); len(variableList.Items) > 0 {
-		variables, err := decodeVariablesHCL(variableList.Items)
+		variables, err := DecodeVariablesHCL(variableList.Items)
 		if err != nil {
 			errors = multierror.Append(errors, err)
 		} else {
@@ -208,10 +215,11 @@ func DecodeHCL(item *ast.ObjectItem) (interface{}, error) {
 	return &p, nil
 }
 
-func decodeURIPathsHCL(items []*ast.ObjectItem) ([]*evURIPath, error) {
-	var uriPaths []*evURIPath
+// DecodeURIPathsHCL converts uri_path items into URIPath objects.
+func DecodeURIPathsHCL(items []*ast.ObjectItem) ([]*URIPath, error) {
+	var uriPaths []*URIPath
 	for _, item := range items {
-		var up evURIPath
+		var up URIPath
 
 		var listVal *ast.ObjectList
 		if ot, ok := item.Val.(*ast.ObjectType); ok {
@@ -230,7 +238,7 @@ func decodeURIPathsHCL(items []*ast.ObjectItem) ([]*evURIPath, error) {
 		}
 
 		if patternList := listVal.Filter("pattern"); len(patternList.Items) > 0 {
-			patterns, err := decodePatternsHCL(patternList.Items)
+			patterns, err := DecodePatternsHCL(patternList.Items)
 			if err != nil {
 				return nil, err
 			}
@@ -243,10 +251,11 @@ func decodeURIPathsHCL(items []*ast.ObjectItem) ([]*evURIPath, error) {
 	return uriPaths, nil
 }
 
-func decodeQueryParamsHCL(items []*ast.ObjectItem) ([]*evQueryParam, error) {
-	var queryParams []*evQueryParam
+// DecodeQueryParamsHCL converts query_param items into QueryParam objects.
+func DecodeQueryParamsHCL(items []*ast.ObjectItem) ([]*QueryParam, error) {
+	var queryParams []*QueryParam
 	for _, item := range items {
-		var qp evQueryParam
+		var qp QueryParam
 
 		var listVal *ast.ObjectList
 		if ot, ok := item.Val.(*ast.ObjectType); ok {
@@ -276,7 +285,7 @@ func decodeQueryParamsHCL(items []*ast.ObjectItem) ([]*evQueryParam, error) {
 		qp.Name = item.Keys[0].Token.Value().(string)
 
 		if patternList := listVal.Filter("pattern"); len(patternList.Items) > 0 {
-			patterns, err := decodePatternsHCL(patternList.Items)
+			patterns, err := DecodePatternsHCL(patternList.Items)
 			if err != nil {
 				return nil, err
 			}
@@ -289,10 +298,11 @@ func decodeQueryParamsHCL(items []*ast.ObjectItem) ([]*evQueryParam, error) {
 	return queryParams, nil
 }
 
-func decodeHeadersHCL(items []*ast.ObjectItem) ([]*evHeader, error) {
-	var headers []*evHeader
+// DecodeHeadersHCL converts header items into Header objects.
+func DecodeHeadersHCL(items []*ast.ObjectItem) ([]*Header, error) {
+	var headers []*Header
 	for _, item := range items {
-		var hdr evHeader
+		var hdr Header
 
 		var listVal *ast.ObjectList
 		if ot, ok := item.Val.(*ast.ObjectType); ok {
@@ -322,7 +332,7 @@ func decodeHeadersHCL(items []*ast.ObjectItem) ([]*evHeader, error) {
 		hdr.Name = item.Keys[0].Token.Value().(string)
 
 		if patternList := listVal.Filter("pattern"); len(patternList.Items) > 0 {
-			patterns, err := decodePatternsHCL(patternList.Items)
+			patterns, err := DecodePatternsHCL(patternList.Items)
 			if err != nil {
 				return nil, err
 			}
@@ -335,10 +345,11 @@ func decodeHeadersHCL(items []*ast.ObjectItem) ([]*evHeader, error) {
 	return headers, nil
 }
 
-func decodeFormParamsHCL(items []*ast.ObjectItem) ([]*evFormParam, error) {
-	var formParams []*evFormParam
+// DecodeFormParamsHCL converts form_param items into FormParam objects.
+func DecodeFormParamsHCL(items []*ast.ObjectItem) ([]*FormParam, error) {
+	var formParams []*FormParam
 	for _, item := range items {
-		var fp evFormParam
+		var fp FormParam
 
 		var listVal *ast.ObjectList
 		if ot, ok := item.Val.(*ast.ObjectType); ok {
@@ -368,7 +379,7 @@ func decodeFormParamsHCL(items []*ast.ObjectItem) ([]*evFormParam, error) {
 		fp.Name = item.Keys[0].Token.Value().(string)
 
 		if patternList := listVal.Filter("pattern"); len(patternList.Items) > 0 {
-			patterns, err := decodePatternsHCL(patternList.Items)
+			patterns, err := DecodePatternsHCL(patternList.Items)
 			if err != nil {
 				return nil, err
 			}
@@ -381,10 +392,11 @@ func decodeFormParamsHCL(items []*ast.ObjectItem) ([]*evFormParam, error) {
 	return formParams, nil
 }
 
-func decodeVariablesHCL(items []*ast.ObjectItem) ([]*evVariable, error) {
-	var variables []*evVariable
+// DecodeVariablesHCL converts variable items into Variable objects.
+func DecodeVariablesHCL(items []*ast.ObjectItem) ([]*Variable, error) {
+	var variables []*Variable
 	for _, item := range items {
-		var v evVariable
+		var v Variable
 
 		var listVal *ast.ObjectList
 		if ot, ok := item.Val.(*ast.ObjectType); ok {
@@ -414,7 +426,7 @@ func decodeVariablesHCL(items []*ast.ObjectItem) ([]*evVariable, error) {
 		v.Name = item.Keys[0].Token.Value().(string)
 
 		if patternList := listVal.Filter("pattern"); len(patternList.Items) > 0 {
-			patterns, err := decodePatternsHCL(patternList.Items)
+			patterns, err := DecodePatternsHCL(patternList.Items)
 			if err != nil {
 				return nil, err
 			}
@@ -513,7 +525,7 @@ func decodeXMLPayloadHCL(item *ast.ObjectItem) (*evXMLPayload, error) {
 	}
 
 	if namespaceList := listVal.Filter("namespace"); len(namespaceList.Items) > 0 {
-		namespaces, err := decodeXMLPayloadNamespacesHCL(namespaceList.Items)
+		namespaces, err := DecodeNamespacesHCL(namespaceList.Items)
 		if err != nil {
 			return nil, err
 		}
@@ -568,10 +580,11 @@ func decodeXMLPayloadVariablesHCL(items []*ast.ObjectItem) ([]*evXMLPayloadVaria
 	return variables, nil
 }
 
-func decodeXMLPayloadNamespacesHCL(items []*ast.ObjectItem) ([]*evXMLPayloadNamespace, error) {
-	var namespaces []*evXMLPayloadNamespace
+// DecodeNamespacesHCL converts namespace items into Namespace objects.
+func DecodeNamespacesHCL(items []*ast.ObjectItem) ([]*Namespace, error) {
+	var namespaces []*Namespace
 	for _, item := range items {
-		var v evXMLPayloadNamespace
+		var v Namespace
 
 		if _, ok := item.Val.(*ast.ObjectType); !ok {
 			pos := item.Val.Pos()
@@ -603,10 +616,11 @@ func decodeXMLPayloadNamespacesHCL(items []*ast.ObjectItem) ([]*evXMLPayloadName
 	return namespaces, nil
 }
 
-func decodePatternsHCL(items []*ast.ObjectItem) ([]*evPattern, error) {
-	var patterns []*evPattern
+// DecodePatternsHCL converts pattern items into Pattern objects.
+func DecodePatternsHCL(items []*ast.ObjectItem) ([]*Pattern, error) {
+	var patterns []*Pattern
 	for _, item := range items {
-		var pat evPattern
+		var pat Pattern
 
 		if _, ok := item.Val.(*ast.ObjectType); !ok {
 			pos := item.Val.Pos()
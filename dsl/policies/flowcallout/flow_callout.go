@@ -0,0 +1,113 @@
+package flowcallout
+
+import (
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/kevinswiber/apigee-hcl/dsl/hclerror"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/policy"
+)
+
+// FlowCallout represents a <FlowCallout/> element.
+//
+// Documentation: http://docs.apigee.com/api-services/reference/flow-callout-policy
+type FlowCallout struct {
+	XMLName          string `xml:"FlowCallout" hcl:"-"`
+	policy.Policy    `hcl:",squash"`
+	DisplayName      string        `xml:",omitempty" hcl:"display_name"`
+	Parameters       *[]*parameter `xml:"Parameters>Parameter" hcl:"parameter"`
+	SharedFlowBundle string        `hcl:"shared_flow_bundle"`
+}
+
+type parameter struct {
+	XMLName string `xml:"Parameter" hcl:"-"`
+	Name    string `xml:"name,attr" hcl:"-"`
+	Ref     string `xml:"ref,attr,omitempty" hcl:"ref"`
+	Value   string `xml:",chardata" hcl:"value"`
+}
+
+// DecodeHCL converts an HCL ast.ObjectItem into a FlowCallout object.
+func DecodeHCL(item *ast.ObjectItem) (interface{}, error) {
+	var errors *multierror.Error
+	var p FlowCallout
+
+	if err := policy.DecodeHCL(item, &p.Policy); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	var listVal *ast.ObjectList
+	if ot, ok := item.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("flow callout policy not an object"),
+		}
+		return nil, &newError
+	}
+
+	if err := hcl.DecodeObject(&p, item.Val.(*ast.ObjectType)); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	if parameterList := listVal.Filter("parameter"); len(parameterList.Items) > 0 {
+		parameters, err := decodeParametersHCL(parameterList.Items)
+		if err != nil {
+			errors = multierror.Append(errors, err)
+		} else {
+			p.Parameters = &parameters
+		}
+	}
+
+	if p.SharedFlowBundle == "" {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("flow callout requires shared_flow_bundle"),
+		}
+		errors = multierror.Append(errors, &newError)
+	}
+
+	if errors != nil {
+		return nil, errors
+	}
+
+	return &p, nil
+}
+
+func decodeParametersHCL(items []*ast.ObjectItem) ([]*parameter, error) {
+	var parameters []*parameter
+	for _, item := range items {
+		var param parameter
+
+		if _, ok := item.Val.(*ast.ObjectType); !ok {
+			pos := item.Val.Pos()
+			newError := hclerror.PosError{
+				Pos: pos,
+				Err: fmt.Errorf("parameter not an object"),
+			}
+			return nil, &newError
+		}
+
+		if err := hcl.DecodeObject(&param, item.Val.(*ast.ObjectType)); err != nil {
+			return nil, err
+		}
+
+		if len(item.Keys) == 0 || item.Keys[0].Token.Value().(string) == "" {
+			pos := item.Val.Pos()
+			newError := hclerror.PosError{
+				Pos: pos,
+				Err: fmt.Errorf("parameter requires a name"),
+			}
+			return nil, &newError
+		}
+
+		param.Name = item.Keys[0].Token.Value().(string)
+		parameters = append(parameters, &param)
+	}
+	return parameters, nil
+}
@@ -0,0 +1,215 @@
+package generatesamlassertion
+
+import (
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/kevinswiber/apigee-hcl/dsl/hclerror"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/extractvariables"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/policy"
+)
+
+// GenerateSAMLAssertion represents a <GenerateSAMLAssertion/> element.
+//
+// Documentation: http://docs.apigee.com/api-services/reference/saml-assertion-policy
+type GenerateSAMLAssertion struct {
+	XMLName                   string `xml:"GenerateSAMLAssertion" hcl:"-"`
+	policy.Policy             `hcl:",squash"`
+	IgnoreContentType         bool            `xml:"ignoreContentType,attr,omitempty" hcl:"ignore_content_type"`
+	DisplayName               string          `xml:",omitempty" hcl:"display_name"`
+	CanonicalizationAlgorithm string          `xml:",omitempty" hcl:"canonicalization_algorithm"`
+	Issuer                    *refValue       `hcl:"issuer"`
+	KeyStore                  *keyStore       `hcl:"key_store"`
+	OutputVariable            *outputVariable `xml:",omitempty" hcl:"output_variable"`
+	SignatureAlgorithm        string          `xml:",omitempty" hcl:"signature_algorithm"`
+	Subject                   *refValue       `xml:",omitempty" hcl:"subject"`
+	Template                  *template       `hcl:"template"`
+}
+
+type refValue struct {
+	Ref   string `xml:"ref,attr,omitempty" hcl:"ref"`
+	Value string `xml:",chardata" hcl:"value"`
+}
+
+type keyStore struct {
+	XMLName string    `xml:"KeyStore" hcl:"-"`
+	Name    *refValue `hcl:"name"`
+	Alias   *refValue `hcl:"alias"`
+}
+
+type outputVariable struct {
+	XMLName      string   `xml:"OutputVariable" hcl:"-"`
+	FlowVariable string   `xml:",omitempty" hcl:"flow_variable"`
+	Message      *message `xml:",omitempty" hcl:"message"`
+}
+
+type message struct {
+	XMLName    string                         `xml:"Message" hcl:"-"`
+	Name       string                         `xml:"name,attr" hcl:"-"`
+	Namespaces *[]*extractvariables.Namespace `xml:"Namespaces>Namespace" hcl:"namespace"`
+	XPath      string                         `xml:",omitempty" hcl:"xpath"`
+}
+
+type template struct {
+	XMLName                   string `xml:"Template" hcl:"-"`
+	IgnoreUnresolvedVariables bool   `xml:"ignoreUnresolvedVariables,attr,omitempty" hcl:"ignore_unresolved_variables"`
+	Content                   string `xml:",cdata" hcl:"content"`
+}
+
+// DecodeHCL converts an HCL ast.ObjectItem into a GenerateSAMLAssertion object.
+func DecodeHCL(item *ast.ObjectItem) (interface{}, error) {
+	var errors *multierror.Error
+	var p GenerateSAMLAssertion
+
+	if err := policy.DecodeHCL(item, &p.Policy); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	var listVal *ast.ObjectList
+	if ot, ok := item.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("generate saml assertion policy not an object"),
+		}
+		return nil, &newError
+	}
+
+	// output_variable is decoded below; its message block carries a label
+	// and repeated namespace blocks that HCL can't decode into a struct.
+	attrs := &ast.ObjectList{}
+	for _, i := range listVal.Items {
+		if len(i.Keys) > 0 && i.Keys[0].Token.Value().(string) != "output_variable" {
+			attrs.Add(i)
+		}
+	}
+
+	if err := hcl.DecodeObject(&p, &ast.ObjectType{List: attrs}); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	if outputList := listVal.Filter("output_variable"); len(outputList.Items) > 0 {
+		output, err := decodeOutputVariableHCL(outputList.Items[0])
+		if err != nil {
+			errors = multierror.Append(errors, err)
+		} else {
+			p.OutputVariable = output
+		}
+	}
+
+	if p.Issuer == nil {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("generate saml assertion requires issuer"),
+		}
+		errors = multierror.Append(errors, &newError)
+	}
+
+	if p.KeyStore == nil || p.KeyStore.Name == nil || p.KeyStore.Alias == nil {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("generate saml assertion requires key_store with name and alias"),
+		}
+		errors = multierror.Append(errors, &newError)
+	}
+
+	if p.Template == nil || p.Template.Content == "" {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("generate saml assertion requires template content"),
+		}
+		errors = multierror.Append(errors, &newError)
+	}
+
+	if errors != nil {
+		return nil, errors
+	}
+
+	return &p, nil
+}
+
+func decodeOutputVariableHCL(item *ast.ObjectItem) (*outputVariable, error) {
+	var o outputVariable
+
+	var listVal *ast.ObjectList
+	if ot, ok := item.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("output_variable not an object"),
+		}
+		return nil, &newError
+	}
+
+	var attrs struct {
+		FlowVariable string `hcl:"flow_variable"`
+	}
+	if err := hcl.DecodeObject(&attrs, item.Val); err != nil {
+		return nil, err
+	}
+	o.FlowVariable = attrs.FlowVariable
+
+	if messageList := listVal.Filter("message"); len(messageList.Items) > 0 {
+		m, err := decodeMessageHCL(messageList.Items[0])
+		if err != nil {
+			return nil, err
+		}
+		o.Message = m
+	}
+
+	return &o, nil
+}
+
+func decodeMessageHCL(item *ast.ObjectItem) (*message, error) {
+	var m message
+
+	var listVal *ast.ObjectList
+	if ot, ok := item.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("message not an object"),
+		}
+		return nil, &newError
+	}
+
+	if len(item.Keys) == 0 || item.Keys[0].Token.Value().(string) == "" {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("message requires a name"),
+		}
+		return nil, &newError
+	}
+	m.Name = item.Keys[0].Token.Value().(string)
+
+	var attrs struct {
+		XPath string `hcl:"xpath"`
+	}
+	if err := hcl.DecodeObject(&attrs, item.Val); err != nil {
+		return nil, err
+	}
+	m.XPath = attrs.XPath
+
+	if namespaceList := listVal.Filter("namespace"); len(namespaceList.Items) > 0 {
+		namespaces, err := extractvariables.DecodeNamespacesHCL(namespaceList.Items)
+		if err != nil {
+			return nil, err
+		}
+		m.Namespaces = &namespaces
+	}
+
+	return &m, nil
+}
@@ -0,0 +1,76 @@
+package getoauthv2info
+
+import (
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/kevinswiber/apigee-hcl/dsl/hclerror"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/policy"
+)
+
+// GetOAuthV2Info represents a <GetOAuthV2Info/> element.
+//
+// Documentation: http://docs.apigee.com/api-services/reference/get-oauth-v2-info-policy
+type GetOAuthV2Info struct {
+	XMLName                 string `xml:"GetOAuthV2Info" hcl:"-"`
+	policy.Policy           `hcl:",squash"`
+	DisplayName             string     `xml:",omitempty" hcl:"display_name"`
+	AccessToken             *reference `xml:",omitempty" hcl:"access_token"`
+	AuthorizationCode       *reference `xml:",omitempty" hcl:"authorization_code"`
+	ClientID                *reference `xml:"ClientId,omitempty" hcl:"client_id"`
+	RefreshToken            *reference `xml:",omitempty" hcl:"refresh_token"`
+	IgnoreAccessTokenStatus bool       `xml:",omitempty" hcl:"ignore_access_token_status"`
+}
+
+type reference struct {
+	Ref string `xml:"ref,attr" hcl:"ref"`
+}
+
+// DecodeHCL converts an HCL ast.ObjectItem into a GetOAuthV2Info object.
+func DecodeHCL(item *ast.ObjectItem) (interface{}, error) {
+	var errors *multierror.Error
+	var p GetOAuthV2Info
+
+	if err := policy.DecodeHCL(item, &p.Policy); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	if _, ok := item.Val.(*ast.ObjectType); !ok {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("get oauth v2 info policy not an object"),
+		}
+		return nil, &newError
+	}
+
+	if err := hcl.DecodeObject(&p, item.Val.(*ast.ObjectType)); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	count := 0
+	for _, r := range []*reference{p.AccessToken, p.AuthorizationCode, p.ClientID, p.RefreshToken} {
+		if r != nil {
+			count++
+		}
+	}
+
+	if count != 1 {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("get oauth v2 info requires exactly one of access_token, " +
+				"authorization_code, client_id, or refresh_token"),
+		}
+		errors = multierror.Append(errors, &newError)
+	}
+
+	if errors != nil {
+		return nil, errors
+	}
+
+	return &p, nil
+}
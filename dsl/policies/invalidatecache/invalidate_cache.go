@@ -0,0 +1,84 @@
+package invalidatecache
+
+import (
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/kevinswiber/apigee-hcl/dsl/hclerror"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/cache"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/policy"
+)
+
+// InvalidateCache represents an <InvalidateCache/> element.
+//
+// Documentation: http://docs.apigee.com/api-services/reference/invalidate-cache-policy
+type InvalidateCache struct {
+	XMLName           string `xml:"InvalidateCache" hcl:"-"`
+	policy.Policy     `hcl:",squash"`
+	DisplayName       string          `xml:",omitempty" hcl:"display_name"`
+	CacheKey          *cache.CacheKey `hcl:"cache_key"`
+	CacheResource     string          `xml:",omitempty" hcl:"cache_resource"`
+	CacheContext      *cacheContext   `xml:",omitempty" hcl:"cache_context"`
+	Scope             string          `xml:",omitempty" hcl:"scope"`
+	PurgeChildEntries bool            `xml:",omitempty" hcl:"purge_child_entries"`
+}
+
+type cacheContext struct {
+	XMLName      string `xml:"CacheContext" hcl:"-"`
+	APIProxyName string `xml:",omitempty" hcl:"api_proxy_name"`
+	ProxyName    string `xml:",omitempty" hcl:"proxy_name"`
+	TargetName   string `xml:",omitempty" hcl:"target_name"`
+}
+
+// DecodeHCL converts an HCL ast.ObjectItem into an InvalidateCache object.
+func DecodeHCL(item *ast.ObjectItem) (interface{}, error) {
+	var errors *multierror.Error
+	var p InvalidateCache
+
+	if err := policy.DecodeHCL(item, &p.Policy); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	var listVal *ast.ObjectList
+	if ot, ok := item.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("invalidate cache policy not an object"),
+		}
+		return nil, &newError
+	}
+
+	if err := hcl.DecodeObject(&p, item.Val.(*ast.ObjectType)); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	if cacheKeyList := listVal.Filter("cache_key"); len(cacheKeyList.Items) > 0 {
+		cacheKey, err := cache.DecodeCacheKeyHCL(cacheKeyList.Items[0])
+		if err != nil {
+			errors = multierror.Append(errors, err)
+		} else {
+			p.CacheKey = cacheKey
+		}
+	}
+
+	if p.CacheKey == nil {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("invalidate cache requires cache_key"),
+		}
+		errors = multierror.Append(errors, &newError)
+	}
+
+	if errors != nil {
+		return nil, errors
+	}
+
+	return &p, nil
+}
@@ -0,0 +1,110 @@
+package javacallout
+
+import (
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/kevinswiber/apigee-hcl/dsl/hclerror"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/policy"
+	"github.com/kevinswiber/apigee-hcl/dsl/properties"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// JavaCallout represents a <JavaCallout/> element.
+//
+// Documentation: http://docs.apigee.com/api-services/reference/java-callout-policy
+type JavaCallout struct {
+	XMLName       string `xml:"JavaCallout" hcl:"-"`
+	policy.Policy `hcl:",squash"`
+	DisplayName   string                  `xml:",omitempty" hcl:"display_name"`
+	ClassName     string                  `hcl:"class_name"`
+	Properties    *[]*properties.Property `xml:"Properties>Property" hcl:"properties"`
+	ResourceURL   string                  `hcl:"resource_url"`
+	Jar           string                  `xml:"-" hcl:"jar"`
+}
+
+// Resource represents an included file in a proxy bundle
+func (j *JavaCallout) Resource() *policy.Resource {
+	return &policy.Resource{
+		URL:  j.ResourceURL,
+		Path: j.Jar,
+	}
+}
+
+// DecodeHCL converts an HCL ast.ObjectItem into a JavaCallout object.
+//
+// A relative jar path is resolved against the directory of the HCL file
+// it's defined in. When resource_url is omitted, it defaults to
+// java://<jar file name>.
+func DecodeHCL(item *ast.ObjectItem) (interface{}, error) {
+	var errors *multierror.Error
+	var p JavaCallout
+
+	if err := policy.DecodeHCL(item, &p.Policy); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	var listVal *ast.ObjectList
+	if ot, ok := item.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("java callout policy not an object"),
+		}
+		return nil, &newError
+	}
+
+	if err := hcl.DecodeObject(&p, item.Val.(*ast.ObjectType)); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	if propsList := listVal.Filter("properties"); len(propsList.Items) > 0 {
+		props, err := properties.DecodeHCL(propsList.Items[0])
+		if err != nil {
+			errors = multierror.Append(errors, err)
+		} else {
+			p.Properties = &props
+		}
+	}
+
+	if p.Jar != "" {
+		if p.ResourceURL == "" {
+			p.ResourceURL = "java://" + path.Base(filepath.ToSlash(p.Jar))
+		}
+
+		if filename := item.Val.Pos().Filename; !filepath.IsAbs(p.Jar) && filename != "" {
+			p.Jar = filepath.Join(filepath.Dir(filename), p.Jar)
+		}
+	}
+
+	if p.ClassName == "" {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("java callout requires class_name"),
+		}
+		errors = multierror.Append(errors, &newError)
+	}
+
+	if !strings.HasPrefix(p.ResourceURL, "java://") {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("java callout requires a jar or a resource_url starting with java://"),
+		}
+		errors = multierror.Append(errors, &newError)
+	}
+
+	if errors != nil {
+		return nil, errors
+	}
+
+	return &p, nil
+}
@@ -0,0 +1,37 @@
+package jsonthreatprotection
+
+import (
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/policy"
+)
+
+// JSONThreatProtection represents a <JSONThreatProtection/> element.
+//
+// Documentation: http://docs.apigee.com/api-services/reference/json-threat-protection-policy
+type JSONThreatProtection struct {
+	XMLName               string `xml:"JSONThreatProtection" hcl:"-"`
+	policy.Policy         `hcl:",squash"`
+	DisplayName           string `xml:",omitempty" hcl:"display_name"`
+	ArrayElementCount     int    `xml:",omitempty" hcl:"array_element_count"`
+	ContainerDepth        int    `xml:",omitempty" hcl:"container_depth"`
+	ObjectEntryCount      int    `xml:",omitempty" hcl:"object_entry_count"`
+	ObjectEntryNameLength int    `xml:",omitempty" hcl:"object_entry_name_length"`
+	Source                string `xml:",omitempty" hcl:"source"`
+	StringValueLength     int    `xml:",omitempty" hcl:"string_value_length"`
+}
+
+// DecodeHCL converts an HCL ast.ObjectItem into a JSONThreatProtection object.
+func DecodeHCL(item *ast.ObjectItem) (interface{}, error) {
+	var p JSONThreatProtection
+
+	if err := policy.DecodeHCL(item, &p.Policy); err != nil {
+		return nil, err
+	}
+
+	if err := hcl.DecodeObject(&p, item.Val.(*ast.ObjectType)); err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
@@ -0,0 +1,64 @@
+package jsontoxml
+
+import (
+	"fmt"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/kevinswiber/apigee-hcl/dsl/hclerror"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/policy"
+)
+
+// JSONToXML represents a <JSONToXML/> element.
+//
+// Documentation: http://docs.apigee.com/api-services/reference/json-xml-policy
+type JSONToXML struct {
+	XMLName        string `xml:"JSONToXML" hcl:"-"`
+	policy.Policy  `hcl:",squash"`
+	DisplayName    string          `xml:",omitempty" hcl:"display_name"`
+	Source         string          `xml:",omitempty" hcl:"source"`
+	OutputVariable string          `xml:",omitempty" hcl:"output_variable"`
+	Options        *jsonXMLOptions `xml:",omitempty" hcl:"options"`
+}
+
+type jsonXMLOptions struct {
+	XMLName                  string `xml:"Options" hcl:"-"`
+	NullValue                string `xml:",omitempty" hcl:"null_value"`
+	NamespaceBlockName       string `xml:",omitempty" hcl:"namespace_block_name"`
+	DefaultNamespaceNodeName string `xml:",omitempty" hcl:"default_namespace_node_name"`
+	NamespaceSeparator       string `xml:",omitempty" hcl:"namespace_separator"`
+	TextNodeName             string `xml:",omitempty" hcl:"text_node_name"`
+	AttributeBlockName       string `xml:",omitempty" hcl:"attribute_block_name"`
+	AttributePrefix          string `xml:",omitempty" hcl:"attribute_prefix"`
+	InvalidCharsReplacement  string `xml:",omitempty" hcl:"invalid_chars_replacement"`
+	ObjectRootElementName    string `xml:",omitempty" hcl:"object_root_element_name"`
+	ArrayRootElementName     string `xml:",omitempty" hcl:"array_root_element_name"`
+	ArrayItemElementName     string `xml:",omitempty" hcl:"array_item_element_name"`
+}
+
+// DecodeHCL converts an HCL ast.ObjectItem into a JSONToXML object.
+func DecodeHCL(item *ast.ObjectItem) (interface{}, error) {
+	var p JSONToXML
+
+	if err := policy.DecodeHCL(item, &p.Policy); err != nil {
+		return nil, err
+	}
+
+	if err := hcl.DecodeObject(&p, item.Val.(*ast.ObjectType)); err != nil {
+		return nil, err
+	}
+
+	if p.Options != nil && p.Options.NamespaceBlockName != "" {
+		if p.Options.DefaultNamespaceNodeName == "" ||
+			p.Options.NamespaceSeparator == "" {
+			pos := item.Val.Pos()
+			newError := hclerror.PosError{
+				Pos: pos,
+				Err: fmt.Errorf("json_to_xml must specify default_namespace_node_name and " +
+					"namespace_separator when namespace_block_name is used"),
+			}
+			return nil, &newError
+		}
+	}
+
+	return &p, nil
+}
@@ -0,0 +1,345 @@
+package keyvaluemapoperations
+
+import (
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/kevinswiber/apigee-hcl/dsl/hclerror"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/policy"
+)
+
+// KeyValueMapOperations represents a <KeyValueMapOperations/> element.
+//
+// Documentation: http://docs.apigee.com/api-services/reference/key-value-map-operations-policy
+type KeyValueMapOperations struct {
+	XMLName          string `xml:"KeyValueMapOperations" hcl:"-"`
+	policy.Policy    `hcl:",squash"`
+	MapIdentifier    string      `xml:"mapIdentifier,attr,omitempty" hcl:"map_identifier"`
+	DisplayName      string      `xml:",omitempty" hcl:"display_name"`
+	InitialEntries   *[]*entry   `xml:"InitialEntries>Entry" hcl:"initial_entry"`
+	Puts             []*put      `xml:"Put,omitempty" hcl:"put"`
+	Gets             []*get      `xml:"Get,omitempty" hcl:"get"`
+	Deletes          []*deleteOp `xml:"Delete,omitempty" hcl:"delete"`
+	ExclusiveCache   bool        `xml:",omitempty" hcl:"exclusive_cache"`
+	ExpiryTimeInSecs int         `xml:",omitempty" hcl:"expiry_time_in_secs"`
+	Scope            string      `xml:",omitempty" hcl:"scope"`
+}
+
+type entry struct {
+	XMLName string   `xml:"Entry" hcl:"-"`
+	Key     *key     `hcl:"key"`
+	Values  []string `xml:"Value" hcl:"value"`
+}
+
+type put struct {
+	XMLName  string       `xml:"Put" hcl:"-"`
+	Override bool         `xml:"override,attr,omitempty" hcl:"override"`
+	Key      *key         `hcl:"key"`
+	Values   []*parameter `xml:"Value" hcl:"value"`
+}
+
+type get struct {
+	XMLName  string `xml:"Get" hcl:"-"`
+	AssignTo string `xml:"assignTo,attr" hcl:"assign_to"`
+	Index    int    `xml:"index,attr,omitempty" hcl:"index"`
+	Key      *key   `hcl:"key"`
+}
+
+type deleteOp struct {
+	XMLName string `xml:"Delete" hcl:"-"`
+	Key     *key   `hcl:"key"`
+}
+
+type key struct {
+	XMLName    string       `xml:"Key" hcl:"-"`
+	Parameters []*parameter `xml:"Parameter" hcl:"parameter"`
+}
+
+// parameter is a key fragment or a value, either a literal or a
+// reference to a flow variable.
+type parameter struct {
+	Ref   string `xml:"ref,attr,omitempty" hcl:"ref"`
+	Value string `xml:",chardata" hcl:"value"`
+}
+
+var operations = map[string]bool{
+	"initial_entry": true,
+	"put":           true,
+	"get":           true,
+	"delete":        true,
+}
+
+// DecodeHCL converts an HCL ast.ObjectItem into a KeyValueMapOperations object.
+func DecodeHCL(item *ast.ObjectItem) (interface{}, error) {
+	var errors *multierror.Error
+	var p KeyValueMapOperations
+
+	if err := policy.DecodeHCL(item, &p.Policy); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	var listVal *ast.ObjectList
+	if ot, ok := item.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("key value map operations policy not an object"),
+		}
+		return nil, &newError
+	}
+
+	// The operation blocks are decoded below; HCL can't decode repeated
+	// blocks with nested lists into a slice of structs.
+	attrs := &ast.ObjectList{}
+	for _, i := range listVal.Items {
+		if len(i.Keys) > 0 && !operations[i.Keys[0].Token.Value().(string)] {
+			attrs.Add(i)
+		}
+	}
+
+	if err := hcl.DecodeObject(&p, &ast.ObjectType{List: attrs}); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	var initialEntries []*entry
+	for _, entryItem := range listVal.Filter("initial_entry").Items {
+		e, err := decodeEntryHCL(entryItem)
+		if err != nil {
+			errors = multierror.Append(errors, err)
+			continue
+		}
+		initialEntries = append(initialEntries, e)
+	}
+	if len(initialEntries) > 0 {
+		p.InitialEntries = &initialEntries
+	}
+
+	for _, putItem := range listVal.Filter("put").Items {
+		pt, err := decodePutHCL(putItem)
+		if err != nil {
+			errors = multierror.Append(errors, err)
+			continue
+		}
+		p.Puts = append(p.Puts, pt)
+	}
+
+	for _, getItem := range listVal.Filter("get").Items {
+		g, err := decodeGetHCL(getItem)
+		if err != nil {
+			errors = multierror.Append(errors, err)
+			continue
+		}
+		p.Gets = append(p.Gets, g)
+	}
+
+	for _, deleteItem := range listVal.Filter("delete").Items {
+		k, err := decodeOperationKeyHCL("delete", deleteItem)
+		if err != nil {
+			errors = multierror.Append(errors, err)
+			continue
+		}
+		p.Deletes = append(p.Deletes, &deleteOp{Key: k})
+	}
+
+	if errors == nil && p.InitialEntries == nil && len(p.Puts) == 0 &&
+		len(p.Gets) == 0 && len(p.Deletes) == 0 {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("key value map operations requires one of initial_entry, " +
+				"put, get, or delete"),
+		}
+		errors = multierror.Append(errors, &newError)
+	}
+
+	if errors != nil {
+		return nil, errors
+	}
+
+	return &p, nil
+}
+
+func decodeEntryHCL(item *ast.ObjectItem) (*entry, error) {
+	var e entry
+
+	k, err := decodeOperationKeyHCL("initial_entry", item)
+	if err != nil {
+		return nil, err
+	}
+	e.Key = k
+
+	var values struct {
+		Values []string `hcl:"value"`
+	}
+	if err := hcl.DecodeObject(&values, item.Val); err != nil {
+		return nil, err
+	}
+	e.Values = values.Values
+
+	if len(e.Values) == 0 {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("initial_entry requires value"),
+		}
+		return nil, &newError
+	}
+
+	return &e, nil
+}
+
+func decodePutHCL(item *ast.ObjectItem) (*put, error) {
+	var pt put
+
+	k, err := decodeOperationKeyHCL("put", item)
+	if err != nil {
+		return nil, err
+	}
+
+	var attrs struct {
+		Override bool `hcl:"override"`
+	}
+	if err := hcl.DecodeObject(&attrs, item.Val); err != nil {
+		return nil, err
+	}
+
+	pt.Key = k
+	pt.Override = attrs.Override
+
+	values, err := decodeParametersHCL(item.Val.(*ast.ObjectType).List.Filter("value").Items)
+	if err != nil {
+		return nil, err
+	}
+	pt.Values = values
+
+	if len(pt.Values) == 0 {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("put requires value"),
+		}
+		return nil, &newError
+	}
+
+	return &pt, nil
+}
+
+func decodeGetHCL(item *ast.ObjectItem) (*get, error) {
+	var g get
+
+	k, err := decodeOperationKeyHCL("get", item)
+	if err != nil {
+		return nil, err
+	}
+
+	var attrs struct {
+		AssignTo string `hcl:"assign_to"`
+		Index    int    `hcl:"index"`
+	}
+	if err := hcl.DecodeObject(&attrs, item.Val); err != nil {
+		return nil, err
+	}
+
+	if attrs.AssignTo == "" {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("get requires assign_to"),
+		}
+		return nil, &newError
+	}
+
+	g.Key = k
+	g.AssignTo = attrs.AssignTo
+	g.Index = attrs.Index
+
+	return &g, nil
+}
+
+// decodeOperationKeyHCL decodes the key block of an initial_entry, put,
+// get or delete block.
+func decodeOperationKeyHCL(operation string, item *ast.ObjectItem) (*key, error) {
+	ot, ok := item.Val.(*ast.ObjectType)
+	if !ok {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("%s not an object", operation),
+		}
+		return nil, &newError
+	}
+
+	keyList := ot.List.Filter("key")
+	if len(keyList.Items) != 1 {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("%s requires exactly one key", operation),
+		}
+		return nil, &newError
+	}
+
+	keyItem := keyList.Items[0]
+	keyVal, ok := keyItem.Val.(*ast.ObjectType)
+	if !ok {
+		pos := keyItem.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("key not an object"),
+		}
+		return nil, &newError
+	}
+
+	parameters, err := decodeParametersHCL(keyVal.List.Filter("parameter").Items)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(parameters) == 0 {
+		pos := keyItem.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("key requires at least one parameter"),
+		}
+		return nil, &newError
+	}
+
+	return &key{Parameters: parameters}, nil
+}
+
+func decodeParametersHCL(items []*ast.ObjectItem) ([]*parameter, error) {
+	var parameters []*parameter
+	for _, item := range items {
+		var param parameter
+
+		if _, ok := item.Val.(*ast.ObjectType); !ok {
+			pos := item.Val.Pos()
+			newError := hclerror.PosError{
+				Pos: pos,
+				Err: fmt.Errorf("parameter not an object"),
+			}
+			return nil, &newError
+		}
+
+		if err := hcl.DecodeObject(&param, item.Val); err != nil {
+			return nil, err
+		}
+
+		if param.Ref == "" && param.Value == "" {
+			pos := item.Val.Pos()
+			newError := hclerror.PosError{
+				Pos: pos,
+				Err: fmt.Errorf("parameter requires ref or value"),
+			}
+			return nil, &newError
+		}
+
+		parameters = append(parameters, &param)
+	}
+	return parameters, nil
+}
@@ -0,0 +1,86 @@
+package lookupcache
+
+import (
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/kevinswiber/apigee-hcl/dsl/hclerror"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/cache"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/policy"
+)
+
+// LookupCache represents a <LookupCache/> element.
+//
+// Documentation: http://docs.apigee.com/api-services/reference/lookup-cache-policy
+type LookupCache struct {
+	XMLName                     string `xml:"LookupCache" hcl:"-"`
+	policy.Policy               `hcl:",squash"`
+	DisplayName                 string          `xml:",omitempty" hcl:"display_name"`
+	CacheKey                    *cache.CacheKey `hcl:"cache_key"`
+	CacheLookupTimeoutInSeconds int             `xml:",omitempty" hcl:"lookup_timeout"`
+	CacheResource               string          `xml:",omitempty" hcl:"cache_resource"`
+	Scope                       string          `xml:",omitempty" hcl:"scope"`
+	AssignTo                    string          `hcl:"assign_to"`
+}
+
+// DecodeHCL converts an HCL ast.ObjectItem into a LookupCache object.
+func DecodeHCL(item *ast.ObjectItem) (interface{}, error) {
+	var errors *multierror.Error
+	var p LookupCache
+
+	if err := policy.DecodeHCL(item, &p.Policy); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	var listVal *ast.ObjectList
+	if ot, ok := item.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("lookup cache policy not an object"),
+		}
+		return nil, &newError
+	}
+
+	if err := hcl.DecodeObject(&p, item.Val.(*ast.ObjectType)); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	if cacheKeyList := listVal.Filter("cache_key"); len(cacheKeyList.Items) > 0 {
+		cacheKey, err := cache.DecodeCacheKeyHCL(cacheKeyList.Items[0])
+		if err != nil {
+			errors = multierror.Append(errors, err)
+		} else {
+			p.CacheKey = cacheKey
+		}
+	}
+
+	if p.CacheKey == nil {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("lookup cache requires cache_key"),
+		}
+		errors = multierror.Append(errors, &newError)
+	}
+
+	if p.AssignTo == "" {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("lookup cache requires assign_to"),
+		}
+		errors = multierror.Append(errors, &newError)
+	}
+
+	if errors != nil {
+		return nil, errors
+	}
+
+	return &p, nil
+}
@@ -0,0 +1,114 @@
+package messagelogging
+
+import (
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/kevinswiber/apigee-hcl/dsl/endpoints"
+	"github.com/kevinswiber/apigee-hcl/dsl/hclerror"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/policy"
+)
+
+// MessageLogging represents a <MessageLogging/> element.
+//
+// Documentation: http://docs.apigee.com/api-services/reference/message-logging-policy
+type MessageLogging struct {
+	XMLName       string `xml:"MessageLogging" hcl:"-"`
+	policy.Policy `hcl:",squash"`
+	DisplayName   string  `xml:",omitempty" hcl:"display_name"`
+	Syslog        *syslog `xml:",omitempty" hcl:"syslog"`
+	File          *file   `xml:",omitempty" hcl:"file"`
+	LogLevel      string  `xml:"logLevel,omitempty" hcl:"log_level"`
+	BufferMessage bool    `xml:",omitempty" hcl:"buffer_message"`
+}
+
+type syslog struct {
+	XMLName       string             `xml:"Syslog" hcl:"-"`
+	Message       string             `hcl:"message"`
+	Host          string             `hcl:"host"`
+	Port          int                `xml:",omitempty" hcl:"port"`
+	Protocol      string             `xml:",omitempty" hcl:"protocol"`
+	FormatMessage bool               `xml:",omitempty" hcl:"format_message"`
+	SSLInfo       *endpoints.SSLInfo `xml:",omitempty" hcl:"ssl_info"`
+}
+
+type file struct {
+	XMLName             string               `xml:"File" hcl:"-"`
+	Message             string               `hcl:"message"`
+	FileName            string               `hcl:"file_name"`
+	FileRotationOptions *fileRotationOptions `xml:",omitempty" hcl:"file_rotation_options"`
+}
+
+type fileRotationOptions struct {
+	XMLName             string             `xml:"FileRotationOptions" hcl:"-"`
+	RotateFileOnStartup bool               `xml:"rotateFileOnStartup,attr,omitempty" hcl:"rotate_file_on_startup"`
+	FileRotationType    string             `xml:",omitempty" hcl:"file_rotation_type"`
+	MaxFileSizeInMB     int                `xml:",omitempty" hcl:"max_file_size_in_mb"`
+	MaxFilesToRetain    int                `xml:",omitempty" hcl:"max_files_to_retain"`
+	RotationFrequency   *rotationFrequency `xml:",omitempty" hcl:"rotation_frequency"`
+}
+
+type rotationFrequency struct {
+	XMLName string `xml:"RotationFrequency" hcl:"-"`
+	Unit    string `xml:"unit,attr,omitempty" hcl:"unit"`
+	Value   int    `xml:",chardata" hcl:"value"`
+}
+
+// DecodeHCL converts an HCL ast.ObjectItem into a MessageLogging object.
+func DecodeHCL(item *ast.ObjectItem) (interface{}, error) {
+	var errors *multierror.Error
+	var p MessageLogging
+
+	if err := policy.DecodeHCL(item, &p.Policy); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	if _, ok := item.Val.(*ast.ObjectType); !ok {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("message logging policy not an object"),
+		}
+		return nil, &newError
+	}
+
+	if err := hcl.DecodeObject(&p, item.Val.(*ast.ObjectType)); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	if (p.Syslog == nil) == (p.File == nil) {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("message logging requires exactly one of syslog or file"),
+		}
+		errors = multierror.Append(errors, &newError)
+	}
+
+	if p.Syslog != nil && (p.Syslog.Message == "" || p.Syslog.Host == "") {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("message logging syslog requires message and host"),
+		}
+		errors = multierror.Append(errors, &newError)
+	}
+
+	if p.File != nil && (p.File.Message == "" || p.File.FileName == "") {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("message logging file requires message and file_name"),
+		}
+		errors = multierror.Append(errors, &newError)
+	}
+
+	if errors != nil {
+		return nil, errors
+	}
+
+	return &p, nil
+}
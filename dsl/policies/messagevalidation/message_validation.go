@@ -0,0 +1,194 @@
+package messagevalidation
+
+import (
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/kevinswiber/apigee-hcl/dsl/hclerror"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/policy"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// MessageValidation represents a <MessageValidation/> element.
+//
+// Documentation: http://docs.apigee.com/api-services/reference/message-validation-policy
+type MessageValidation struct {
+	XMLName       string `xml:"MessageValidation" hcl:"-"`
+	policy.Policy `hcl:",squash"`
+	DisplayName   string       `xml:",omitempty" hcl:"display_name"`
+	Elements      []*element   `xml:"Element,omitempty" hcl:"element"`
+	SOAPMessage   *soapMessage `xml:",omitempty" hcl:"soap_message"`
+	Source        string       `xml:",omitempty" hcl:"source"`
+	ResourceURL   string       `xml:",omitempty" hcl:"resource_url"`
+	Content       string       `xml:"-" hcl:"content"`
+	File          string       `xml:"-" hcl:"file"`
+}
+
+type element struct {
+	XMLName   string `xml:"Element" hcl:"-"`
+	Namespace string `xml:"namespace,attr,omitempty" hcl:"namespace"`
+	Name      string `xml:",chardata" hcl:"-"`
+}
+
+type soapMessage struct {
+	XMLName string `xml:"SOAPMessage" hcl:"-"`
+	Version string `xml:"version,attr,omitempty" hcl:"version"`
+}
+
+var soapVersions = []string{"1.1", "1.2", "1.1/1.2"}
+
+// Resource represents an included file in a proxy bundle
+func (m *MessageValidation) Resource() *policy.Resource {
+	return &policy.Resource{
+		URL:     m.ResourceURL,
+		Content: m.Content,
+		Path:    m.File,
+	}
+}
+
+// DecodeHCL converts an HCL ast.ObjectItem into a MessageValidation object.
+//
+// The WSDL or XSD is given inline as content or as a local file. A relative
+// file path is resolved against the directory of the HCL file it's defined
+// in. When resource_url is omitted, it defaults to wsdl:// or xsd:// and
+// the file name, based on the file's extension.
+// The file isn't read here; it's loaded when the bundle is rendered,
+// through the ReadFile hook in bundle.Options.
+func DecodeHCL(item *ast.ObjectItem) (interface{}, error) {
+	var errors *multierror.Error
+	var p MessageValidation
+
+	if err := policy.DecodeHCL(item, &p.Policy); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	var listVal *ast.ObjectList
+	if ot, ok := item.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("message validation policy not an object"),
+		}
+		return nil, &newError
+	}
+
+	if err := hcl.DecodeObject(&p, item.Val.(*ast.ObjectType)); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	if elementList := listVal.Filter("element"); len(elementList.Items) > 0 {
+		elements, err := decodeElementsHCL(elementList.Items)
+		if err != nil {
+			errors = multierror.Append(errors, err)
+		} else {
+			p.Elements = elements
+		}
+	}
+
+	if p.SOAPMessage != nil && p.SOAPMessage.Version != "" && !validSOAPVersion(p.SOAPMessage.Version) {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("soap_message version must be one of %v, got %q",
+				soapVersions, p.SOAPMessage.Version),
+		}
+		errors = multierror.Append(errors, &newError)
+	}
+
+	if p.Content != "" && p.File != "" {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("message validation accepts only one of content or file"),
+		}
+		errors = multierror.Append(errors, &newError)
+	}
+
+	if p.File != "" {
+		if p.ResourceURL == "" {
+			name := path.Base(filepath.ToSlash(p.File))
+			if ext := strings.TrimPrefix(path.Ext(name), "."); ext == "wsdl" || ext == "xsd" {
+				p.ResourceURL = ext + "://" + name
+			}
+		}
+
+		if filename := item.Val.Pos().Filename; !filepath.IsAbs(p.File) && filename != "" {
+			p.File = filepath.Join(filepath.Dir(filename), p.File)
+		}
+	}
+
+	if p.ResourceURL != "" && !strings.HasPrefix(p.ResourceURL, "wsdl://") &&
+		!strings.HasPrefix(p.ResourceURL, "xsd://") {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("message validation resource_url must start with wsdl:// or xsd://"),
+		}
+		errors = multierror.Append(errors, &newError)
+	}
+
+	if p.ResourceURL == "" && (p.Content != "" || p.File != "") {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("message validation requires a resource_url for its content"),
+		}
+		errors = multierror.Append(errors, &newError)
+	}
+
+	if errors != nil {
+		return nil, errors
+	}
+
+	return &p, nil
+}
+
+func validSOAPVersion(version string) bool {
+	for _, v := range soapVersions {
+		if v == version {
+			return true
+		}
+	}
+
+	return false
+}
+
+func decodeElementsHCL(items []*ast.ObjectItem) ([]*element, error) {
+	var elements []*element
+	for _, item := range items {
+		var e element
+
+		if _, ok := item.Val.(*ast.ObjectType); !ok {
+			pos := item.Val.Pos()
+			newError := hclerror.PosError{
+				Pos: pos,
+				Err: fmt.Errorf("element not an object"),
+			}
+			return nil, &newError
+		}
+
+		if err := hcl.DecodeObject(&e, item.Val.(*ast.ObjectType)); err != nil {
+			return nil, err
+		}
+
+		if len(item.Keys) == 0 || item.Keys[0].Token.Value().(string) == "" {
+			pos := item.Val.Pos()
+			newError := hclerror.PosError{
+				Pos: pos,
+				Err: fmt.Errorf("element requires a name"),
+			}
+			return nil, &newError
+		}
+
+		e.Name = item.Keys[0].Token.Value().(string)
+		elements = append(elements, &e)
+	}
+	return elements, nil
+}
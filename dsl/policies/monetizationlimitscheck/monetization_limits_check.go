@@ -0,0 +1,80 @@
+package monetizationlimitscheck
+
+import (
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/kevinswiber/apigee-hcl/dsl/hclerror"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/policy"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/raisefault"
+)
+
+// MonetizationLimitsCheck represents a <MonetizationLimitsCheck/> element.
+//
+// Documentation: http://docs.apigee.com/monetization/content/enforce-monetization-limits-api-proxies
+type MonetizationLimitsCheck struct {
+	XMLName                   string `xml:"MonetizationLimitsCheck" hcl:"-"`
+	policy.Policy             `hcl:",squash"`
+	DisplayName               string                    `xml:",omitempty" hcl:"display_name"`
+	FaultResponse             *raisefault.FaultResponse `xml:",omitempty" hcl:"fault_response"`
+	Variables                 *variables                `xml:",omitempty" hcl:"variables"`
+	IgnoreUnresolvedVariables bool                      `xml:",omitempty" hcl:"ignore_unresolved_variables"`
+}
+
+type variables struct {
+	XMLName string `xml:"Variables" hcl:"-"`
+	Product string `hcl:"product"`
+}
+
+// DecodeHCL converts an HCL ast.ObjectItem into a MonetizationLimitsCheck object.
+func DecodeHCL(item *ast.ObjectItem) (interface{}, error) {
+	var errors *multierror.Error
+	var p MonetizationLimitsCheck
+
+	if err := policy.DecodeHCL(item, &p.Policy); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	var listVal *ast.ObjectList
+	if ot, ok := item.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("monetization limits check policy not an object"),
+		}
+		return nil, &newError
+	}
+
+	if err := hcl.DecodeObject(&p, item.Val.(*ast.ObjectType)); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	if faultResponseList := listVal.Filter("fault_response"); len(faultResponseList.Items) > 0 {
+		faultResponse, err := raisefault.DecodeFaultResponseHCL(faultResponseList.Items[0])
+		if err != nil {
+			errors = multierror.Append(errors, err)
+		} else {
+			p.FaultResponse = faultResponse
+		}
+	}
+
+	if p.Variables != nil && p.Variables.Product == "" {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("monetization limits check variables requires product"),
+		}
+		errors = multierror.Append(errors, &newError)
+	}
+
+	if errors != nil {
+		return nil, errors
+	}
+
+	return &p, nil
+}
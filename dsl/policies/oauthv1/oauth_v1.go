@@ -0,0 +1,160 @@
+package oauthv1
+
+import (
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/kevinswiber/apigee-hcl/dsl/hclerror"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/policy"
+)
+
+// OAuthV1 represents an <OAuthV1/> element.
+//
+// Documentation: http://docs.apigee.com/api-services/reference/oauth-v10a-policy
+type OAuthV1 struct {
+	XMLName               string `xml:"OAuthV1" hcl:"-"`
+	policy.Policy         `hcl:",squash"`
+	DisplayName           string            `xml:",omitempty" hcl:"display_name"`
+	Operation             string            `hcl:"operation"`
+	URL                   *refValue         `xml:",omitempty" hcl:"url"`
+	RequestToken          *refValue         `xml:",omitempty" hcl:"request_token"`
+	VerifierCode          *refValue         `xml:",omitempty" hcl:"verifier_code"`
+	AccessToken           *refValue         `xml:",omitempty" hcl:"access_token"`
+	AppKey                *refValue         `xml:",omitempty" hcl:"app_key"`
+	ExpiresIn             *refValue         `xml:",omitempty" hcl:"expires_in"`
+	Attributes            *[]*attribute     `xml:"Attributes>Attribute" hcl:"attribute"`
+	GenerateResponse      *generateResponse `xml:",omitempty" hcl:"generate_response"`
+	GenerateErrorResponse *generateResponse `xml:",omitempty" hcl:"generate_error_response"`
+}
+
+type refValue struct {
+	Ref   string `xml:"ref,attr,omitempty" hcl:"ref"`
+	Value string `xml:",chardata" hcl:"value"`
+}
+
+type attribute struct {
+	XMLName string `xml:"Attribute" hcl:"-"`
+	Name    string `xml:"name,attr" hcl:"-"`
+	Ref     string `xml:"ref,attr,omitempty" hcl:"ref"`
+	Display bool   `xml:"display,attr,omitempty" hcl:"display"`
+	Value   string `xml:",chardata" hcl:"value"`
+}
+
+type generateResponse struct {
+	Enabled bool   `xml:"enabled,attr" hcl:"enabled"`
+	Format  string `xml:",omitempty" hcl:"format"`
+	Realm   string `xml:",omitempty" hcl:"realm"`
+}
+
+var operations = []string{
+	"GenerateAccessToken",
+	"GenerateRequestToken",
+	"GenerateVerifier",
+	"VerifyAccessToken",
+	"VerifyAPIKey",
+}
+
+// DecodeHCL converts an HCL ast.ObjectItem into an OAuthV1 object.
+func DecodeHCL(item *ast.ObjectItem) (interface{}, error) {
+	var errors *multierror.Error
+	var p OAuthV1
+
+	if err := policy.DecodeHCL(item, &p.Policy); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	var listVal *ast.ObjectList
+	if ot, ok := item.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("oauth v1 policy not an object"),
+		}
+		return nil, &newError
+	}
+
+	if err := hcl.DecodeObject(&p, item.Val.(*ast.ObjectType)); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	if attributeList := listVal.Filter("attribute"); len(attributeList.Items) > 0 {
+		attributes, err := decodeAttributesHCL(attributeList.Items)
+		if err != nil {
+			errors = multierror.Append(errors, err)
+		} else {
+			p.Attributes = &attributes
+		}
+	}
+
+	if !validOperation(p.Operation) {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("oauth v1 operation must be one of %v, got %q", operations, p.Operation),
+		}
+		errors = multierror.Append(errors, &newError)
+	}
+
+	if p.Operation == "VerifyAPIKey" && p.AppKey == nil {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("oauth v1 VerifyAPIKey operation requires app_key"),
+		}
+		errors = multierror.Append(errors, &newError)
+	}
+
+	if errors != nil {
+		return nil, errors
+	}
+
+	return &p, nil
+}
+
+func validOperation(operation string) bool {
+	for _, o := range operations {
+		if o == operation {
+			return true
+		}
+	}
+
+	return false
+}
+
+func decodeAttributesHCL(items []*ast.ObjectItem) ([]*attribute, error) {
+	var attributes []*attribute
+	for _, item := range items {
+		var a attribute
+
+		if _, ok := item.Val.(*ast.ObjectType); !ok {
+			pos := item.Val.Pos()
+			newError := hclerror.PosError{
+				Pos: pos,
+				Err: fmt.Errorf("attribute not an object"),
+			}
+			return nil, &newError
+		}
+
+		if err := hcl.DecodeObject(&a, item.Val.(*ast.ObjectType)); err != nil {
+			return nil, err
+		}
+
+		if len(item.Keys) == 0 || item.Keys[0].Token.Value().(string) == "" {
+			pos := item.Val.Pos()
+			newError := hclerror.PosError{
+				Pos: pos,
+				Err: fmt.Errorf("attribute requires a name"),
+			}
+			return nil, &newError
+		}
+
+		a.Name = item.Keys[0].Token.Value().(string)
+		attributes = append(attributes, &a)
+	}
+	return attributes, nil
+}
@@ -0,0 +1,209 @@
+package oauthv2
+
+import (
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/kevinswiber/apigee-hcl/dsl/hclerror"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/policy"
+)
+
+// OAuthV2 represents an <OAuthV2/> element.
+//
+// Documentation: http://docs.apigee.com/api-services/reference/oauthv2-policy
+type OAuthV2 struct {
+	XMLName                     string `xml:"OAuthV2" hcl:"-"`
+	policy.Policy               `hcl:",squash"`
+	DisplayName                 string             `xml:",omitempty" hcl:"display_name"`
+	Operation                   string             `hcl:"operation"`
+	AccessToken                 string             `xml:",omitempty" hcl:"access_token"`
+	AccessTokenPrefix           string             `xml:",omitempty" hcl:"access_token_prefix"`
+	AppEndUser                  string             `xml:",omitempty" hcl:"app_end_user"`
+	Attributes                  *[]*attribute      `xml:"Attributes>Attribute" hcl:"attribute"`
+	CacheExpiryInSeconds        *refValue          `xml:",omitempty" hcl:"cache_expiry_in_seconds"`
+	ClientID                    string             `xml:"ClientId,omitempty" hcl:"client_id"`
+	Code                        string             `xml:",omitempty" hcl:"code"`
+	ExpiresIn                   *refValue          `xml:",omitempty" hcl:"expires_in"`
+	ExternalAccessToken         string             `xml:",omitempty" hcl:"external_access_token"`
+	ExternalAuthorization       bool               `xml:",omitempty" hcl:"external_authorization"`
+	ExternalAuthorizationCode   string             `xml:",omitempty" hcl:"external_authorization_code"`
+	ExternalRefreshToken        string             `xml:",omitempty" hcl:"external_refresh_token"`
+	GenerateErrorResponse       *generateResponse  `xml:",omitempty" hcl:"generate_error_response"`
+	GenerateResponse            *generateResponse  `xml:",omitempty" hcl:"generate_response"`
+	GrantType                   string             `xml:",omitempty" hcl:"grant_type"`
+	PassWord                    string             `xml:",omitempty" hcl:"password"`
+	RedirectURI                 string             `xml:"RedirectUri,omitempty" hcl:"redirect_uri"`
+	RefreshToken                string             `xml:",omitempty" hcl:"refresh_token"`
+	RefreshTokenExpiresIn       *refValue          `xml:",omitempty" hcl:"refresh_token_expires_in"`
+	ResponseType                string             `xml:",omitempty" hcl:"response_type"`
+	ReuseRefreshToken           bool               `xml:",omitempty" hcl:"reuse_refresh_token"`
+	RFCCompliantRequestResponse bool               `xml:",omitempty" hcl:"rfc_compliant_request_response"`
+	Scope                       string             `xml:",omitempty" hcl:"scope"`
+	State                       string             `xml:",omitempty" hcl:"state"`
+	StoreToken                  bool               `xml:",omitempty" hcl:"store_token"`
+	SupportedGrantTypes         *[]string          `xml:"SupportedGrantTypes>GrantType" hcl:"supported_grant_types"`
+	Tokens                      *[]*tokenReference `xml:"Tokens>Token" hcl:"token"`
+	UserName                    string             `xml:",omitempty" hcl:"user_name"`
+}
+
+type attribute struct {
+	XMLName string `xml:"Attribute" hcl:"-"`
+	Name    string `xml:"name,attr" hcl:"-"`
+	Ref     string `xml:"ref,attr,omitempty" hcl:"ref"`
+	Display bool   `xml:"display,attr,omitempty" hcl:"display"`
+	Value   string `xml:",chardata" hcl:"value"`
+}
+
+type refValue struct {
+	Ref   string `xml:"ref,attr,omitempty" hcl:"ref"`
+	Value string `xml:",chardata" hcl:"value"`
+}
+
+type generateResponse struct {
+	Enabled bool `xml:"enabled,attr" hcl:"enabled"`
+}
+
+type tokenReference struct {
+	XMLName string `xml:"Token" hcl:"-"`
+	Type    string `xml:"type,attr,omitempty" hcl:"type"`
+	Cascade bool   `xml:"cascade,attr,omitempty" hcl:"cascade"`
+	Value   string `xml:",chardata" hcl:"value"`
+}
+
+var operations = []string{
+	"GenerateAccessToken",
+	"GenerateAccessTokenImplicitGrant",
+	"GenerateAuthorizationCode",
+	"InvalidateToken",
+	"RefreshAccessToken",
+	"ValidateToken",
+	"VerifyAccessToken",
+}
+
+// DecodeHCL converts an HCL ast.ObjectItem into an OAuthV2 object.
+func DecodeHCL(item *ast.ObjectItem) (interface{}, error) {
+	var errors *multierror.Error
+	var p OAuthV2
+
+	if err := policy.DecodeHCL(item, &p.Policy); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	var listVal *ast.ObjectList
+	if ot, ok := item.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("oauth v2 policy not an object"),
+		}
+		return nil, &newError
+	}
+
+	if err := hcl.DecodeObject(&p, item.Val.(*ast.ObjectType)); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	if attributeList := listVal.Filter("attribute"); len(attributeList.Items) > 0 {
+		attributes, err := decodeAttributesHCL(attributeList.Items)
+		if err != nil {
+			errors = multierror.Append(errors, err)
+		} else {
+			p.Attributes = &attributes
+		}
+	}
+
+	if tokenList := listVal.Filter("token"); len(tokenList.Items) > 0 {
+		tokens, err := decodeTokensHCL(tokenList.Items)
+		if err != nil {
+			errors = multierror.Append(errors, err)
+		} else {
+			p.Tokens = &tokens
+		}
+	}
+
+	if !validOperation(p.Operation) {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("oauth v2 operation must be one of %v, got %q", operations, p.Operation),
+		}
+		errors = multierror.Append(errors, &newError)
+	}
+
+	if errors != nil {
+		return nil, errors
+	}
+
+	return &p, nil
+}
+
+func validOperation(operation string) bool {
+	for _, o := range operations {
+		if o == operation {
+			return true
+		}
+	}
+
+	return false
+}
+
+func decodeAttributesHCL(items []*ast.ObjectItem) ([]*attribute, error) {
+	var attributes []*attribute
+	for _, item := range items {
+		var a attribute
+
+		if _, ok := item.Val.(*ast.ObjectType); !ok {
+			pos := item.Val.Pos()
+			newError := hclerror.PosError{
+				Pos: pos,
+				Err: fmt.Errorf("attribute not an object"),
+			}
+			return nil, &newError
+		}
+
+		if err := hcl.DecodeObject(&a, item.Val.(*ast.ObjectType)); err != nil {
+			return nil, err
+		}
+
+		if len(item.Keys) == 0 || item.Keys[0].Token.Value().(string) == "" {
+			pos := item.Val.Pos()
+			newError := hclerror.PosError{
+				Pos: pos,
+				Err: fmt.Errorf("attribute requires a name"),
+			}
+			return nil, &newError
+		}
+
+		a.Name = item.Keys[0].Token.Value().(string)
+		attributes = append(attributes, &a)
+	}
+	return attributes, nil
+}
+
+func decodeTokensHCL(items []*ast.ObjectItem) ([]*tokenReference, error) {
+	var tokens []*tokenReference
+	for _, item := range items {
+		var t tokenReference
+
+		if _, ok := item.Val.(*ast.ObjectType); !ok {
+			pos := item.Val.Pos()
+			newError := hclerror.PosError{
+				Pos: pos,
+				Err: fmt.Errorf("token not an object"),
+			}
+			return nil, &newError
+		}
+
+		if err := hcl.DecodeObject(&t, item.Val.(*ast.ObjectType)); err != nil {
+			return nil, err
+		}
+
+		tokens = append(tokens, &t)
+	}
+	return tokens, nil
+}
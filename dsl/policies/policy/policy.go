@@ -1,19 +1,22 @@
 package policy
 
 import (
+	"encoding/xml"
 	"fmt"
 	"github.com/hashicorp/hcl"
 	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/hashicorp/hcl/hcl/token"
 )
 
 // Policy Represents a base Policy element. Each policy type should embed a Policy.
 //
 // Documentation: http://docs.apigee.com/api-services/reference/api-proxy-configuration-reference#policies
 type Policy struct {
-	InternalName    string `xml:"name,attr,omitempty" hcl:"-"`
-	Enabled         bool   `xml:"enabled,attr" hcl:"enabled"`
-	ContinueOnError bool   `xml:"continueOnError,attr,omitempty" hcl:"continue_on_error"`
-	Async           bool   `xml:"async,attr,omitempty" hcl:"async"`
+	InternalName    string    `xml:"name,attr,omitempty" hcl:"-"`
+	Enabled         bool      `xml:"enabled,attr" hcl:"enabled"`
+	ContinueOnError bool      `xml:"continueOnError,attr,omitempty" hcl:"continue_on_error"`
+	Async           bool      `xml:"async,attr,omitempty" hcl:"async"`
+	Pos             token.Pos `xml:"-" hcl:"-"`
 }
 
 // Namer is used to set and retrieve a policy name
@@ -32,15 +35,34 @@ func (p *Policy) SetName(name string) {
 	p.InternalName = name
 }
 
+// Marshaler is implemented by policies that marshal their own XML
+// document. Policy types registered with dsl.RegisterPolicy implement it.
+type Marshaler interface {
+	Namer
+	xml.Marshaler
+}
+
+// Positioner is used to retrieve a policy's position in its HCL source
+type Positioner interface {
+	Position() token.Pos
+}
+
+// Position returns the position of the policy in its HCL source.
+func (p *Policy) Position() token.Pos {
+	return p.Pos
+}
+
 // Resourcer is used for policies with resources
 type Resourcer interface {
 	Resource() *Resource
 }
 
-// Resource represents an included file in a proxy bundle
+// Resource represents an included file in a proxy bundle. Path names a
+// local file to copy into the bundle when there is no inline Content.
 type Resource struct {
 	URL     string
 	Content string
+	Path    string
 }
 
 // DecodeHCL converts an HCL ast.ObjectItem into a Policy object.
@@ -61,6 +83,7 @@ func DecodeHCL(item *ast.ObjectItem, p *Policy) error {
 	}
 
 	p.SetName(item.Keys[1].Token.Value().(string))
+	p.Pos = item.Pos()
 
 	return nil
 }
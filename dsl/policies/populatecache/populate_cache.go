@@ -0,0 +1,86 @@
+package populatecache
+
+import (
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/kevinswiber/apigee-hcl/dsl/hclerror"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/cache"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/policy"
+)
+
+// PopulateCache represents a <PopulateCache/> element.
+//
+// Documentation: http://docs.apigee.com/api-services/reference/populate-cache-policy
+type PopulateCache struct {
+	XMLName        string `xml:"PopulateCache" hcl:"-"`
+	policy.Policy  `hcl:",squash"`
+	DisplayName    string                `xml:",omitempty" hcl:"display_name"`
+	CacheKey       *cache.CacheKey       `hcl:"cache_key"`
+	CacheResource  string                `xml:",omitempty" hcl:"cache_resource"`
+	Scope          string                `xml:",omitempty" hcl:"scope"`
+	ExpirySettings *cache.ExpirySettings `xml:",omitempty" hcl:"expiry_settings"`
+	Source         string                `hcl:"source"`
+}
+
+// DecodeHCL converts an HCL ast.ObjectItem into a PopulateCache object.
+func DecodeHCL(item *ast.ObjectItem) (interface{}, error) {
+	var errors *multierror.Error
+	var p PopulateCache
+
+	if err := policy.DecodeHCL(item, &p.Policy); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	var listVal *ast.ObjectList
+	if ot, ok := item.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("populate cache policy not an object"),
+		}
+		return nil, &newError
+	}
+
+	if err := hcl.DecodeObject(&p, item.Val.(*ast.ObjectType)); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	if cacheKeyList := listVal.Filter("cache_key"); len(cacheKeyList.Items) > 0 {
+		cacheKey, err := cache.DecodeCacheKeyHCL(cacheKeyList.Items[0])
+		if err != nil {
+			errors = multierror.Append(errors, err)
+		} else {
+			p.CacheKey = cacheKey
+		}
+	}
+
+	if p.CacheKey == nil {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("populate cache requires cache_key"),
+		}
+		errors = multierror.Append(errors, &newError)
+	}
+
+	if p.Source == "" {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("populate cache requires source"),
+		}
+		errors = multierror.Append(errors, &newError)
+	}
+
+	if errors != nil {
+		return nil, errors
+	}
+
+	return &p, nil
+}
@@ -31,7 +31,7 @@ type allow struct {
 	XMLName  string   `xml:"Allow" hcl:"-"`
 	Count    int      `xml:"count,attr,omitempty" hcl:"count"`
 	CountRef string   `xml:"countRef,attr,omitempty" hcl:"count_ref"`
-	Classes  []*class `xml:",omitempty" hcl:"class"`
+	Classes  []*class `xml:"Class,omitempty" hcl:"class"`
 }
 
 type class struct {
@@ -15,11 +15,13 @@ type RaiseFault struct {
 	XMLName                   string `xml:"RaiseFault" hcl:"-"`
 	policy.Policy             `hcl:",squash"`
 	DisplayName               string         `xml:",omitempty" hcl:"display_name"`
-	FaultResponse             *faultResponse `xml:"FaultResponse" hcl:"fault_response"`
+	FaultResponse             *FaultResponse `xml:"FaultResponse" hcl:"fault_response"`
 	IgnoreUnresolvedVariables bool           `xml:"IgnoreUnresolvedVariables" hcl:"ignore_unresolved_variables"`
 }
 
-type faultResponse struct {
+// FaultResponse represents the <FaultResponse/> element of a policy that
+// returns a custom error.
+type FaultResponse struct {
 	Copy   *raiseFaultCopy   `xml:",omitempty" hcl:"copy"`
 	Remove *raiseFaultRemove `xml:",omitempty" hcl:"remove"`
 	Set    *raiseFaultSet    `xml:",omitempty" hcl:"set"`
@@ -71,7 +73,7 @@ func DecodeHCL(item *ast.ObjectItem) (interface{}, error) {
 
 	if faultResponseList := listVal.Filter("fault_response"); len(faultResponseList.Items) > 0 {
 		item := faultResponseList.Items[0]
-		a, err := decodeFaultResponse(item)
+		a, err := DecodeFaultResponseHCL(item)
 		if err != nil {
 			return nil, err
 		}
@@ -83,8 +85,9 @@ func DecodeHCL(item *ast.ObjectItem) (interface{}, error) {
 	return &p, nil
 }
 
-func decodeFaultResponse(item *ast.ObjectItem) (*faultResponse, error) {
-	var result *faultResponse
+// DecodeFaultResponseHCL converts a fault_response item into a FaultResponse.
+func DecodeFaultResponseHCL(item *ast.ObjectItem) (*FaultResponse, error) {
+	var result *FaultResponse
 
 	if err := hcl.DecodeObject(&result, item.Val.(*ast.ObjectType)); err != nil {
 		return nil, err
@@ -0,0 +1,277 @@
+package regularexpressionprotection
+
+import (
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/kevinswiber/apigee-hcl/dsl/hclerror"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/extractvariables"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/policy"
+)
+
+// RegularExpressionProtection represents a <RegularExpressionProtection/> element.
+//
+// Documentation: http://docs.apigee.com/api-services/reference/regular-expression-protection
+type RegularExpressionProtection struct {
+	XMLName                   string `xml:"RegularExpressionProtection" hcl:"-"`
+	policy.Policy             `hcl:",squash"`
+	DisplayName               string                         `xml:",omitempty" hcl:"display_name"`
+	Source                    string                         `xml:",omitempty" hcl:"source"`
+	IgnoreUnresolvedVariables bool                           `xml:",omitempty" hcl:"ignore_unresolved_variables"`
+	URIPaths                  []*extractvariables.URIPath    `xml:"URIPath,omitempty" hcl:"uri_path"`
+	QueryParams               []*extractvariables.QueryParam `xml:"QueryParam,omitempty" hcl:"query_param"`
+	Headers                   []*extractvariables.Header     `xml:"Header,omitempty" hcl:"header"`
+	FormParams                []*extractvariables.FormParam  `xml:"FormParam,omitempty" hcl:"form_param"`
+	Variables                 []*extractvariables.Variable   `xml:"Variable,omitempty" hcl:"variable"`
+	XMLPayload                *xmlPayload                    `xml:",omitempty" hcl:"xml_payload"`
+	JSONPayload               *jsonPayload                   `xml:",omitempty" hcl:"json_payload"`
+}
+
+type xmlPayload struct {
+	XMLName    string                         `xml:"XMLPayload" hcl:"-"`
+	Namespaces *[]*extractvariables.Namespace `xml:"Namespaces>Namespace" hcl:"namespace"`
+	XPaths     []*xpath                       `xml:"XPath" hcl:"xpath"`
+}
+
+type xpath struct {
+	XMLName    string                      `xml:"XPath" hcl:"-"`
+	Expression string                      `hcl:"expression"`
+	Type       string                      `xml:",omitempty" hcl:"type"`
+	Patterns   []*extractvariables.Pattern `xml:"Pattern" hcl:"pattern"`
+}
+
+type jsonPayload struct {
+	XMLName   string      `xml:"JSONPayload" hcl:"-"`
+	JSONPaths []*jsonPath `xml:"JSONPath" hcl:"json_path"`
+}
+
+type jsonPath struct {
+	XMLName    string                      `xml:"JSONPath" hcl:"-"`
+	Expression string                      `hcl:"expression"`
+	Patterns   []*extractvariables.Pattern `xml:"Pattern" hcl:"pattern"`
+}
+
+// DecodeHCL converts an HCL ast.ObjectItem into a RegularExpressionProtection object.
+func DecodeHCL(item *ast.ObjectItem) (interface{}, error) {
+	var errors *multierror.Error
+	var p RegularExpressionProtection
+
+	if err := policy.DecodeHCL(item, &p.Policy); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	var listVal *ast.ObjectList
+	if ot, ok := item.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("regular expression protection policy not an object"),
+		}
+		return nil, &newError
+	}
+
+	if err := hcl.DecodeObject(&p, item.Val.(*ast.ObjectType)); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	if uriPathList := listVal.Filter("uri_path"); len(uriPathList.Items) > 0 {
+		uriPaths, err := extractvariables.DecodeURIPathsHCL(uriPathList.Items)
+		if err != nil {
+			errors = multierror.Append(errors, err)
+		} else {
+			p.URIPaths = uriPaths
+		}
+	}
+
+	if queryParamList := listVal.Filter("query_param"); len(queryParamList.Items) > 0 {
+		queryParams, err := extractvariables.DecodeQueryParamsHCL(queryParamList.Items)
+		if err != nil {
+			errors = multierror.Append(errors, err)
+		} else {
+			p.QueryParams = queryParams
+		}
+	}
+
+	if headerList := listVal.Filter("header"); len(headerList.Items) > 0 {
+		headers, err := extractvariables.DecodeHeadersHCL(headerList.Items)
+		if err != nil {
+			errors = multierror.Append(errors, err)
+		} else {
+			p.Headers = headers
+		}
+	}
+
+	if formParamList := listVal.Filter("form_param"); len(formParamList.Items) > 0 {
+		formParams, err := extractvariables.DecodeFormParamsHCL(formParamList.Items)
+		if err != nil {
+			errors = multierror.Append(errors, err)
+		} else {
+			p.FormParams = formParams
+		}
+	}
+
+	if variableList := listVal.Filter("variable"); len(variableList.Items) > 0 {
+		variables, err := extractvariables.DecodeVariablesHCL(variableList.Items)
+		if err != nil {
+			errors = multierror.Append(errors, err)
+		} else {
+			p.Variables = variables
+		}
+	}
+
+	if xmlPayloadList := listVal.Filter("xml_payload"); len(xmlPayloadList.Items) > 0 {
+		xmlPayload, err := decodeXMLPayloadHCL(xmlPayloadList.Items[0])
+		if err != nil {
+			errors = multierror.Append(errors, err)
+		} else {
+			p.XMLPayload = xmlPayload
+		}
+	}
+
+	if jsonPayloadList := listVal.Filter("json_payload"); len(jsonPayloadList.Items) > 0 {
+		jsonPayload, err := decodeJSONPayloadHCL(jsonPayloadList.Items[0])
+		if err != nil {
+			errors = multierror.Append(errors, err)
+		} else {
+			p.JSONPayload = jsonPayload
+		}
+	}
+
+	if len(p.URIPaths) == 0 && len(p.QueryParams) == 0 && len(p.Headers) == 0 &&
+		len(p.FormParams) == 0 && len(p.Variables) == 0 &&
+		p.XMLPayload == nil && p.JSONPayload == nil {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("regular expression protection requires one of uri_path, " +
+				"query_param, header, form_param, variable, xml_payload, or json_payload"),
+		}
+		errors = multierror.Append(errors, &newError)
+	}
+
+	if errors != nil {
+		return nil, errors
+	}
+
+	return &p, nil
+}
+
+func decodeXMLPayloadHCL(item *ast.ObjectItem) (*xmlPayload, error) {
+	var p xmlPayload
+
+	var listVal *ast.ObjectList
+	if ot, ok := item.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("xml_payload not an object"),
+		}
+		return nil, &newError
+	}
+
+	if namespaceList := listVal.Filter("namespace"); len(namespaceList.Items) > 0 {
+		namespaces, err := extractvariables.DecodeNamespacesHCL(namespaceList.Items)
+		if err != nil {
+			return nil, err
+		}
+
+		p.Namespaces = &namespaces
+	}
+
+	for _, xpathItem := range listVal.Filter("xpath").Items {
+		var x xpath
+
+		expression, patterns, err := decodeExpressionHCL("xpath", xpathItem)
+		if err != nil {
+			return nil, err
+		}
+
+		var attrs struct {
+			Type string `hcl:"type"`
+		}
+		if err := hcl.DecodeObject(&attrs, xpathItem.Val); err != nil {
+			return nil, err
+		}
+
+		x.Expression = expression
+		x.Type = attrs.Type
+		x.Patterns = patterns
+		p.XPaths = append(p.XPaths, &x)
+	}
+
+	return &p, nil
+}
+
+func decodeJSONPayloadHCL(item *ast.ObjectItem) (*jsonPayload, error) {
+	var p jsonPayload
+
+	var listVal *ast.ObjectList
+	if ot, ok := item.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("json_payload not an object"),
+		}
+		return nil, &newError
+	}
+
+	for _, jsonPathItem := range listVal.Filter("json_path").Items {
+		expression, patterns, err := decodeExpressionHCL("json_path", jsonPathItem)
+		if err != nil {
+			return nil, err
+		}
+
+		p.JSONPaths = append(p.JSONPaths, &jsonPath{
+			Expression: expression,
+			Patterns:   patterns,
+		})
+	}
+
+	return &p, nil
+}
+
+// decodeExpressionHCL decodes the expression and patterns of an xpath
+// or json_path block.
+func decodeExpressionHCL(key string, item *ast.ObjectItem) (string, []*extractvariables.Pattern, error) {
+	ot, ok := item.Val.(*ast.ObjectType)
+	if !ok {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("%s not an object", key),
+		}
+		return "", nil, &newError
+	}
+
+	var attrs struct {
+		Expression string `hcl:"expression"`
+	}
+	if err := hcl.DecodeObject(&attrs, item.Val); err != nil {
+		return "", nil, err
+	}
+
+	if attrs.Expression == "" {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("%s requires expression", key),
+		}
+		return "", nil, &newError
+	}
+
+	patterns, err := extractvariables.DecodePatternsHCL(ot.List.Filter("pattern").Items)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return attrs.Expression, patterns, nil
+}
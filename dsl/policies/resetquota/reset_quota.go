@@ -0,0 +1,173 @@
+package resetquota
+
+import (
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/kevinswiber/apigee-hcl/dsl/hclerror"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/policy"
+)
+
+// ResetQuota represents a <ResetQuota/> element.
+//
+// Documentation: http://docs.apigee.com/api-services/reference/reset-quota-policy
+type ResetQuota struct {
+	XMLName       string `xml:"ResetQuota" hcl:"-"`
+	policy.Policy `hcl:",squash"`
+	DisplayName   string `xml:",omitempty" hcl:"display_name"`
+	Quota         *Quota `hcl:"quota"`
+}
+
+// Quota represents the <Quota/> element naming the Quota policy to reset.
+type Quota struct {
+	XMLName     string        `xml:"Quota" hcl:"-"`
+	Name        string        `xml:"name,attr" hcl:"-"`
+	Identifiers []*identifier `xml:"Identifier" hcl:"identifier"`
+}
+
+type identifier struct {
+	XMLName string `xml:"Identifier" hcl:"-"`
+	Name    string `xml:"name,attr,omitempty" hcl:"-"`
+	Ref     string `xml:"ref,attr,omitempty" hcl:"ref"`
+	Allow   *allow `hcl:"allow"`
+}
+
+type allow struct {
+	XMLName string `xml:"Allow" hcl:"-"`
+	Ref     string `xml:"ref,attr,omitempty" hcl:"ref"`
+	Value   string `xml:",chardata" hcl:"value"`
+}
+
+// DecodeHCL converts an HCL ast.ObjectItem into a ResetQuota object.
+func DecodeHCL(item *ast.ObjectItem) (interface{}, error) {
+	var errors *multierror.Error
+	var p ResetQuota
+
+	if err := policy.DecodeHCL(item, &p.Policy); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	var listVal *ast.ObjectList
+	if ot, ok := item.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("reset quota policy not an object"),
+		}
+		return nil, &newError
+	}
+
+	var attrs struct {
+		DisplayName string `hcl:"display_name"`
+	}
+	if err := hcl.DecodeObject(&attrs, item.Val); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+	p.DisplayName = attrs.DisplayName
+
+	quotaList := listVal.Filter("quota")
+	if len(quotaList.Items) != 1 {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("reset quota requires exactly one quota"),
+		}
+		errors = multierror.Append(errors, &newError)
+	} else {
+		q, err := decodeQuotaHCL(quotaList.Items[0])
+		if err != nil {
+			errors = multierror.Append(errors, err)
+		} else {
+			p.Quota = q
+		}
+	}
+
+	if errors != nil {
+		return nil, errors
+	}
+
+	return &p, nil
+}
+
+func decodeQuotaHCL(item *ast.ObjectItem) (*Quota, error) {
+	var q Quota
+
+	var listVal *ast.ObjectList
+	if ot, ok := item.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("quota not an object"),
+		}
+		return nil, &newError
+	}
+
+	if len(item.Keys) == 0 || item.Keys[0].Token.Value().(string) == "" {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("quota requires the name of a quota policy"),
+		}
+		return nil, &newError
+	}
+	q.Name = item.Keys[0].Token.Value().(string)
+
+	for _, identifierItem := range listVal.Filter("identifier").Items {
+		var id identifier
+
+		if _, ok := identifierItem.Val.(*ast.ObjectType); !ok {
+			pos := identifierItem.Val.Pos()
+			newError := hclerror.PosError{
+				Pos: pos,
+				Err: fmt.Errorf("identifier not an object"),
+			}
+			return nil, &newError
+		}
+
+		if err := hcl.DecodeObject(&id, identifierItem.Val); err != nil {
+			return nil, err
+		}
+
+		if len(identifierItem.Keys) > 0 {
+			id.Name = identifierItem.Keys[0].Token.Value().(string)
+		}
+
+		if (id.Name == "") == (id.Ref == "") {
+			pos := identifierItem.Val.Pos()
+			newError := hclerror.PosError{
+				Pos: pos,
+				Err: fmt.Errorf("identifier requires either a name or a ref"),
+			}
+			return nil, &newError
+		}
+
+		if id.Allow == nil {
+			pos := identifierItem.Val.Pos()
+			newError := hclerror.PosError{
+				Pos: pos,
+				Err: fmt.Errorf("identifier requires allow"),
+			}
+			return nil, &newError
+		}
+
+		q.Identifiers = append(q.Identifiers, &id)
+	}
+
+	if len(q.Identifiers) == 0 {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("quota requires at least one identifier"),
+		}
+		return nil, &newError
+	}
+
+	return &q, nil
+}
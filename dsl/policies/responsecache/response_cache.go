@@ -3,6 +3,7 @@ package responsecache
 import (
 	"github.com/hashicorp/hcl"
 	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/cache"
 	"github.com/kevinswiber/apigee-hcl/dsl/policies/policy"
 )
 
@@ -12,55 +13,18 @@ import (
 type ResponseCache struct {
 	XMLName                     string `xml:"ResponseCache" hcl:"-"`
 	policy.Policy               `hcl:",squash"`
-	Type                        string          `xml:"type,attr,omitempty" hcl:"type"`
-	DisplayName                 string          `xml:",omitempty" hcl:"display_name"`
-	CacheKey                    *cacheKey       `xml:"CacheKey" hcl:"cache_key"`
-	Scope                       string          `xml:",omitempty" hcl:"scope"`
-	ExpirySettings              *expirySettings `xml:"ExpirySettings" hcl:"expiry_settings"`
-	CacheResource               string          `xml:",omitempty" hcl:"cache_resource"`
-	CacheLookupTimeoutInSeconds int             `xml:",omitempty" hcl:"lookup_timeout"`
-	ExcludeErrorResponse        bool            `xml:",omitempty" hcl:"exclude_error_response"`
-	SkipCacheLookup             string          `xml:",omitempty" hcl:"skip_cache_lookup"`
-	SkipCachePopulation         string          `xml:",omitempty" hcl:"skip_cache_population"`
-	UseAcceptHeader             bool            `xml:",omitempty" hcl:"use_accept_header"`
-	UseResponseCacheHeaders     bool            `xml:",omitempty" hcl:"use_response_cache_headers"`
-}
-
-type cacheKey struct {
-	XMLName     string              `xml:"CacheKey" hcl:"-"`
-	Prefix      string              `xml:",omitempty" hcl:"prefix"`
-	KeyFragment []*cacheKeyFragment `xml:",omitempty" hcl:"key_fragment"`
-}
-
-type cacheKeyFragment struct {
-	XMLName string `xml:"KeyFragment" hcl:"-"`
-	Ref     string `xml:"ref,attr,omitempty" hcl:"ref"`
-	Value   string `xml:",chardata" hcl:"value"`
-}
-
-type expirySettings struct {
-	XMLName      string              `xml:"ExpirySettings" hcl:"-"`
-	TimeOfDay    *expiryTimeOfDay    `xml:",omitempty" hcl:"time_of_day"`
-	TimeoutInSec *expiryTimeoutInSec `xml:",omitempty" hcl:"timeout_in_sec"`
-	ExpiryDate   *expiryDate         `xml:",omitempty" hcl:"expiry_date"`
-}
-
-type expiryTimeOfDay struct {
-	XMLName string `xml:"TimeOfDay" hcl:"-"`
-	Ref     string `xml:"ref,attr,omitempty" hcl:"ref"`
-	Value   string `xml:",chardata" hcl:"value"`
-}
-
-type expiryTimeoutInSec struct {
-	XMLName string `xml:"TimeoutInSec" hcl:"-"`
-	Ref     string `xml:"ref,attr,omitempty" hcl:"ref"`
-	Value   string `xml:",chardata" hcl:"value"`
-}
-
-type expiryDate struct {
-	XMLName string `xml:"ExpiryDate" hcl:"-"`
-	Ref     string `xml:"ref,attr,omitempty" hcl:"ref"`
-	Value   string `xml:",chardata" hcl:"value"`
+	Type                        string                `xml:"type,attr,omitempty" hcl:"type"`
+	DisplayName                 string                `xml:",omitempty" hcl:"display_name"`
+	CacheKey                    *cache.CacheKey       `xml:"CacheKey" hcl:"cache_key"`
+	Scope                       string                `xml:",omitempty" hcl:"scope"`
+	ExpirySettings              *cache.ExpirySettings `xml:"ExpirySettings" hcl:"expiry_settings"`
+	CacheResource               string                `xml:",omitempty" hcl:"cache_resource"`
+	CacheLookupTimeoutInSeconds int                   `xml:",omitempty" hcl:"lookup_timeout"`
+	ExcludeErrorResponse        bool                  `xml:",omitempty" hcl:"exclude_error_response"`
+	SkipCacheLookup             string                `xml:",omitempty" hcl:"skip_cache_lookup"`
+	SkipCachePopulation         string                `xml:",omitempty" hcl:"skip_cache_population"`
+	UseAcceptHeader             bool                  `xml:",omitempty" hcl:"use_accept_header"`
+	UseResponseCacheHeaders     bool                  `xml:",omitempty" hcl:"use_response_cache_headers"`
 }
 
 // DecodeHCL converts an HCL ast.ObjectItem into a ResponseCache
@@ -75,5 +39,14 @@ func DecodeHCL(item *ast.ObjectItem) (interface{}, error) {
 		return nil, err
 	}
 
+	if cacheKeyList := item.Val.(*ast.ObjectType).List.Filter("cache_key"); len(cacheKeyList.Items) > 0 {
+		cacheKey, err := cache.DecodeCacheKeyHCL(cacheKeyList.Items[0])
+		if err != nil {
+			return nil, err
+		}
+
+		p.CacheKey = cacheKey
+	}
+
 	return &p, nil
 }
@@ -0,0 +1,117 @@
+package setoauthv2info
+
+import (
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/kevinswiber/apigee-hcl/dsl/hclerror"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/policy"
+)
+
+// SetOAuthV2Info represents a <SetOAuthV2Info/> element.
+//
+// Documentation: http://docs.apigee.com/api-services/reference/set-oauth-v2-info-policy
+type SetOAuthV2Info struct {
+	XMLName       string `xml:"SetOAuthV2Info" hcl:"-"`
+	policy.Policy `hcl:",squash"`
+	DisplayName   string        `xml:",omitempty" hcl:"display_name"`
+	AccessToken   *reference    `hcl:"access_token"`
+	Attributes    *[]*attribute `xml:"Attributes>Attribute" hcl:"attribute"`
+}
+
+type reference struct {
+	Ref string `xml:"ref,attr" hcl:"ref"`
+}
+
+type attribute struct {
+	XMLName string `xml:"Attribute" hcl:"-"`
+	Name    string `xml:"name,attr" hcl:"-"`
+	Ref     string `xml:"ref,attr,omitempty" hcl:"ref"`
+	Value   string `xml:",chardata" hcl:"value"`
+}
+
+// DecodeHCL converts an HCL ast.ObjectItem into a SetOAuthV2Info object.
+func DecodeHCL(item *ast.ObjectItem) (interface{}, error) {
+	var errors *multierror.Error
+	var p SetOAuthV2Info
+
+	if err := policy.DecodeHCL(item, &p.Policy); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	var listVal *ast.ObjectList
+	if ot, ok := item.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("set oauth v2 info policy not an object"),
+		}
+		return nil, &newError
+	}
+
+	if err := hcl.DecodeObject(&p, item.Val.(*ast.ObjectType)); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	if p.AccessToken == nil {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("set oauth v2 info requires access_token"),
+		}
+		errors = multierror.Append(errors, &newError)
+	}
+
+	if attributeList := listVal.Filter("attribute"); len(attributeList.Items) > 0 {
+		attributes, err := decodeAttributesHCL(attributeList.Items)
+		if err != nil {
+			errors = multierror.Append(errors, err)
+		} else {
+			p.Attributes = &attributes
+		}
+	}
+
+	if errors != nil {
+		return nil, errors
+	}
+
+	return &p, nil
+}
+
+func decodeAttributesHCL(items []*ast.ObjectItem) ([]*attribute, error) {
+	var attributes []*attribute
+	for _, item := range items {
+		var a attribute
+
+		if _, ok := item.Val.(*ast.ObjectType); !ok {
+			pos := item.Val.Pos()
+			newError := hclerror.PosError{
+				Pos: pos,
+				Err: fmt.Errorf("attribute not an object"),
+			}
+			return nil, &newError
+		}
+
+		if err := hcl.DecodeObject(&a, item.Val.(*ast.ObjectType)); err != nil {
+			return nil, err
+		}
+
+		if len(item.Keys) == 0 || item.Keys[0].Token.Value().(string) == "" {
+			pos := item.Val.Pos()
+			newError := hclerror.PosError{
+				Pos: pos,
+				Err: fmt.Errorf("attribute requires a name"),
+			}
+			return nil, &newError
+		}
+
+		a.Name = item.Keys[0].Token.Value().(string)
+		attributes = append(attributes, &a)
+	}
+	return attributes, nil
+}
@@ -0,0 +1,153 @@
+package validatesamlassertion
+
+import (
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/kevinswiber/apigee-hcl/dsl/hclerror"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/extractvariables"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/policy"
+)
+
+// ValidateSAMLAssertion represents a <ValidateSAMLAssertion/> element.
+//
+// Documentation: http://docs.apigee.com/api-services/reference/saml-assertion-policy
+type ValidateSAMLAssertion struct {
+	XMLName           string `xml:"ValidateSAMLAssertion" hcl:"-"`
+	policy.Policy     `hcl:",squash"`
+	IgnoreContentType bool    `xml:"ignoreContentType,attr,omitempty" hcl:"ignore_content_type"`
+	DisplayName       string  `xml:",omitempty" hcl:"display_name"`
+	Source            *source `hcl:"source"`
+	TrustStore        string  `hcl:"trust_store"`
+	RemoveAssertion   bool    `xml:",omitempty" hcl:"remove_assertion"`
+}
+
+type source struct {
+	XMLName    string                         `xml:"Source" hcl:"-"`
+	Name       string                         `xml:"name,attr" hcl:"-"`
+	Namespaces *[]*extractvariables.Namespace `xml:"Namespaces>Namespace" hcl:"namespace"`
+	XPath      string                         `hcl:"xpath"`
+}
+
+// DecodeHCL converts an HCL ast.ObjectItem into a ValidateSAMLAssertion object.
+func DecodeHCL(item *ast.ObjectItem) (interface{}, error) {
+	var errors *multierror.Error
+	var p ValidateSAMLAssertion
+
+	if err := policy.DecodeHCL(item, &p.Policy); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	var listVal *ast.ObjectList
+	if ot, ok := item.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("validate saml assertion policy not an object"),
+		}
+		return nil, &newError
+	}
+
+	var attrs struct {
+		IgnoreContentType bool   `hcl:"ignore_content_type"`
+		DisplayName       string `hcl:"display_name"`
+		TrustStore        string `hcl:"trust_store"`
+		RemoveAssertion   bool   `hcl:"remove_assertion"`
+	}
+	if err := hcl.DecodeObject(&attrs, item.Val); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+	p.IgnoreContentType = attrs.IgnoreContentType
+	p.DisplayName = attrs.DisplayName
+	p.TrustStore = attrs.TrustStore
+	p.RemoveAssertion = attrs.RemoveAssertion
+
+	if sourceList := listVal.Filter("source"); len(sourceList.Items) > 0 {
+		s, err := decodeSourceHCL(sourceList.Items[0])
+		if err != nil {
+			errors = multierror.Append(errors, err)
+		} else {
+			p.Source = s
+		}
+	} else {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("validate saml assertion requires source"),
+		}
+		errors = multierror.Append(errors, &newError)
+	}
+
+	if p.TrustStore == "" {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("validate saml assertion requires trust_store"),
+		}
+		errors = multierror.Append(errors, &newError)
+	}
+
+	if errors != nil {
+		return nil, errors
+	}
+
+	return &p, nil
+}
+
+func decodeSourceHCL(item *ast.ObjectItem) (*source, error) {
+	var s source
+
+	var listVal *ast.ObjectList
+	if ot, ok := item.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("source not an object"),
+		}
+		return nil, &newError
+	}
+
+	if len(item.Keys) == 0 || item.Keys[0].Token.Value().(string) == "" {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("source requires a message name"),
+		}
+		return nil, &newError
+	}
+	s.Name = item.Keys[0].Token.Value().(string)
+
+	var attrs struct {
+		XPath string `hcl:"xpath"`
+	}
+	if err := hcl.DecodeObject(&attrs, item.Val); err != nil {
+		return nil, err
+	}
+	s.XPath = attrs.XPath
+
+	if s.XPath == "" {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("source requires xpath"),
+		}
+		return nil, &newError
+	}
+
+	if namespaceList := listVal.Filter("namespace"); len(namespaceList.Items) > 0 {
+		namespaces, err := extractvariables.DecodeNamespacesHCL(namespaceList.Items)
+		if err != nil {
+			return nil, err
+		}
+		s.Namespaces = &namespaces
+	}
+
+	return &s, nil
+}
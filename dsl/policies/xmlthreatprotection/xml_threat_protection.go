@@ -0,0 +1,100 @@
+package xmlthreatprotection
+
+import (
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/kevinswiber/apigee-hcl/dsl/hclerror"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/policy"
+)
+
+// XMLThreatProtection represents an <XMLThreatProtection/> element.
+//
+// Documentation: http://docs.apigee.com/api-services/reference/xml-threat-protection-policy
+type XMLThreatProtection struct {
+	XMLName         string `xml:"XMLThreatProtection" hcl:"-"`
+	policy.Policy   `hcl:",squash"`
+	DisplayName     string           `xml:",omitempty" hcl:"display_name"`
+	NameLimits      *nameLimits      `xml:",omitempty" hcl:"name_limits"`
+	Source          string           `xml:",omitempty" hcl:"source"`
+	StructureLimits *structureLimits `xml:",omitempty" hcl:"structure_limits"`
+	ValueLimits     *valueLimits     `xml:",omitempty" hcl:"value_limits"`
+}
+
+type nameLimits struct {
+	XMLName                     string `xml:"NameLimits" hcl:"-"`
+	Element                     int    `xml:",omitempty" hcl:"element"`
+	Attribute                   int    `xml:",omitempty" hcl:"attribute"`
+	NamespacePrefix             int    `xml:",omitempty" hcl:"namespace_prefix"`
+	ProcessingInstructionTarget int    `xml:",omitempty" hcl:"processing_instruction_target"`
+}
+
+type structureLimits struct {
+	XMLName                  string      `xml:"StructureLimits" hcl:"-"`
+	NodeDepth                int         `xml:",omitempty" hcl:"node_depth"`
+	AttributeCountPerElement int         `xml:",omitempty" hcl:"attribute_count_per_element"`
+	NamespaceCountPerElement int         `xml:",omitempty" hcl:"namespace_count_per_element"`
+	ChildCount               *childCount `xml:",omitempty" hcl:"child_count"`
+}
+
+// childCount leaves unset include flags out of the XML, where they
+// default to true.
+type childCount struct {
+	XMLName                      string `xml:"ChildCount" hcl:"-"`
+	IncludeComment               *bool  `xml:"includeComment,attr" hcl:"include_comment"`
+	IncludeElement               *bool  `xml:"includeElement,attr" hcl:"include_element"`
+	IncludeProcessingInstruction *bool  `xml:"includeProcessingInstruction,attr" hcl:"include_processing_instruction"`
+	IncludeText                  *bool  `xml:"includeText,attr" hcl:"include_text"`
+	Value                        int    `xml:",chardata" hcl:"value"`
+}
+
+type valueLimits struct {
+	XMLName                   string `xml:"ValueLimits" hcl:"-"`
+	Text                      int    `xml:",omitempty" hcl:"text"`
+	Attribute                 int    `xml:",omitempty" hcl:"attribute"`
+	NamespaceURI              int    `xml:",omitempty" hcl:"namespace_uri"`
+	Comment                   int    `xml:",omitempty" hcl:"comment"`
+	ProcessingInstructionData int    `xml:",omitempty" hcl:"processing_instruction_data"`
+}
+
+// DecodeHCL converts an HCL ast.ObjectItem into an XMLThreatProtection object.
+func DecodeHCL(item *ast.ObjectItem) (interface{}, error) {
+	var errors *multierror.Error
+	var p XMLThreatProtection
+
+	if err := policy.DecodeHCL(item, &p.Policy); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	if _, ok := item.Val.(*ast.ObjectType); !ok {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("xml threat protection policy not an object"),
+		}
+		return nil, &newError
+	}
+
+	if err := hcl.DecodeObject(&p, item.Val.(*ast.ObjectType)); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	if p.NameLimits == nil && p.StructureLimits == nil && p.ValueLimits == nil {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("xml threat protection requires one of name_limits, " +
+				"structure_limits, or value_limits"),
+		}
+		errors = multierror.Append(errors, &newError)
+	}
+
+	if errors != nil {
+		return nil, errors
+	}
+
+	return &p, nil
+}
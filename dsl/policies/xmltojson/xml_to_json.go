@@ -62,6 +62,14 @@ func DecodeHCL(item *ast.ObjectItem) (interface{}, error) {
 		return nil, err
 	}
 
+	if p.Options != nil && p.Options.TreatAsArray != nil {
+		paths, err := decodePathsHCL(item.Val.(*ast.ObjectType).List)
+		if err != nil {
+			return nil, err
+		}
+		p.Options.TreatAsArray.Paths = paths
+	}
+
 	if p.Options != nil && p.Format != "" {
 		pos := item.Val.Pos()
 		newError := hclerror.PosError{
@@ -95,3 +103,43 @@ func DecodeHCL(item *ast.ObjectItem) (interface{}, error) {
 
 	return &p, nil
 }
+
+// decodePathsHCL decodes the path blocks in options.treat_as_array one
+// at a time, as decoding them into a slice splits a block's attributes
+// into separate paths.
+func decodePathsHCL(list *ast.ObjectList) (*[]*xmlJSONPath, error) {
+	var paths []*xmlJSONPath
+
+	var items []*ast.ObjectItem
+	for _, options := range list.Filter("options").Items {
+		if ot, ok := options.Val.(*ast.ObjectType); ok {
+			for _, treatAsArray := range ot.List.Filter("treat_as_array").Items {
+				if ot, ok := treatAsArray.Val.(*ast.ObjectType); ok {
+					items = append(items, ot.List.Filter("path").Items...)
+				}
+			}
+		}
+	}
+
+	for _, item := range items {
+		ot, ok := item.Val.(*ast.ObjectType)
+		if !ok {
+			return nil, &hclerror.PosError{
+				Pos: item.Val.Pos(),
+				Err: fmt.Errorf("treat_as_array path not an object"),
+			}
+		}
+
+		var path xmlJSONPath
+		if err := hcl.DecodeObject(&path, ot); err != nil {
+			return nil, err
+		}
+		paths = append(paths, &path)
+	}
+
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	return &paths, nil
+}
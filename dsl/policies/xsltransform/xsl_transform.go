@@ -0,0 +1,186 @@
+package xsltransform
+
+import (
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/kevinswiber/apigee-hcl/dsl/hclerror"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/policy"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// XSLTransform represents an <XSL/> element.
+//
+// Documentation: http://docs.apigee.com/api-services/reference/xsl-transform-policy
+type XSLTransform struct {
+	XMLName        string `xml:"XSL" hcl:"-"`
+	policy.Policy  `hcl:",squash"`
+	DisplayName    string      `xml:",omitempty" hcl:"display_name"`
+	Source         string      `xml:",omitempty" hcl:"source"`
+	ResourceURL    string      `hcl:"resource_url"`
+	Parameters     *parameters `xml:",omitempty" hcl:"parameters"`
+	OutputVariable string      `xml:",omitempty" hcl:"output_variable"`
+	Content        string      `xml:"-" hcl:"content"`
+	File           string      `xml:"-" hcl:"file"`
+}
+
+type parameters struct {
+	XMLName                   string       `xml:"Parameters" hcl:"-"`
+	IgnoreUnresolvedVariables bool         `xml:"ignoreUnresolvedVariables,attr,omitempty" hcl:"ignore_unresolved_variables"`
+	Parameters                []*parameter `xml:"Parameter" hcl:"parameter"`
+}
+
+type parameter struct {
+	XMLName string `xml:"Parameter" hcl:"-"`
+	Name    string `xml:"name,attr" hcl:"-"`
+	Ref     string `xml:"ref,attr,omitempty" hcl:"ref"`
+	Value   string `xml:"value,attr,omitempty" hcl:"value"`
+}
+
+// Resource represents an included file in a proxy bundle
+func (x *XSLTransform) Resource() *policy.Resource {
+	return &policy.Resource{
+		URL:     x.ResourceURL,
+		Content: x.Content,
+		Path:    x.File,
+	}
+}
+
+// DecodeHCL converts an HCL ast.ObjectItem into an XSLTransform object.
+//
+// The stylesheet is given inline as content or as a local file. A relative
+// file path is resolved against the directory of the HCL file it's defined
+// in. When resource_url is omitted, it defaults to xsl:// and the file name.
+func DecodeHCL(item *ast.ObjectItem) (interface{}, error) {
+	var errors *multierror.Error
+	var p XSLTransform
+
+	if err := policy.DecodeHCL(item, &p.Policy); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	var listVal *ast.ObjectList
+	if ot, ok := item.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("xsl transform policy not an object"),
+		}
+		return nil, &newError
+	}
+
+	if err := hcl.DecodeObject(&p, item.Val.(*ast.ObjectType)); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	if p.Content != "" && p.File != "" {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("xsl transform accepts only one of content or file"),
+		}
+		errors = multierror.Append(errors, &newError)
+	}
+
+	if p.File != "" {
+		if p.ResourceURL == "" {
+			p.ResourceURL = "xsl://" + path.Base(filepath.ToSlash(p.File))
+		}
+
+		if filename := item.Val.Pos().Filename; !filepath.IsAbs(p.File) && filename != "" {
+			p.File = filepath.Join(filepath.Dir(filename), p.File)
+		}
+	}
+
+	if !strings.HasPrefix(p.ResourceURL, "xsl://") {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("xsl transform resource_url must start with xsl://"),
+		}
+		errors = multierror.Append(errors, &newError)
+	}
+
+	if parametersList := listVal.Filter("parameters"); len(parametersList.Items) > 0 {
+		params, err := decodeParametersHCL(parametersList.Items[0])
+		if err != nil {
+			errors = multierror.Append(errors, err)
+		} else {
+			p.Parameters = params
+		}
+	}
+
+	if errors != nil {
+		return nil, errors
+	}
+
+	return &p, nil
+}
+
+func decodeParametersHCL(item *ast.ObjectItem) (*parameters, error) {
+	var params parameters
+
+	var listVal *ast.ObjectList
+	if ot, ok := item.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("parameters not an object"),
+		}
+		return nil, &newError
+	}
+
+	if err := hcl.DecodeObject(&params, item.Val.(*ast.ObjectType)); err != nil {
+		return nil, err
+	}
+
+	params.Parameters = nil
+	for _, paramItem := range listVal.Filter("parameter").Items {
+		var param parameter
+
+		if _, ok := paramItem.Val.(*ast.ObjectType); !ok {
+			pos := paramItem.Val.Pos()
+			newError := hclerror.PosError{
+				Pos: pos,
+				Err: fmt.Errorf("parameter not an object"),
+			}
+			return nil, &newError
+		}
+
+		if err := hcl.DecodeObject(&param, paramItem.Val.(*ast.ObjectType)); err != nil {
+			return nil, err
+		}
+
+		if len(paramItem.Keys) == 0 || paramItem.Keys[0].Token.Value().(string) == "" {
+			pos := paramItem.Val.Pos()
+			newError := hclerror.PosError{
+				Pos: pos,
+				Err: fmt.Errorf("parameter requires a name"),
+			}
+			return nil, &newError
+		}
+
+		if (param.Ref == "") == (param.Value == "") {
+			pos := paramItem.Val.Pos()
+			newError := hclerror.PosError{
+				Pos: pos,
+				Err: fmt.Errorf("parameter requires exactly one of ref or value"),
+			}
+			return nil, &newError
+		}
+
+		param.Name = paramItem.Keys[0].Token.Value().(string)
+		params.Parameters = append(params.Parameters, &param)
+	}
+
+	return &params, nil
+}
@@ -1,33 +1,170 @@
 package dsl
 
 import (
+	"fmt"
 	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/kevinswiber/apigee-hcl/dsl/hclerror"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/accesscontrol"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/accessentity"
 	"github.com/kevinswiber/apigee-hcl/dsl/policies/assignmessage"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/basicauthentication"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/concurrentratelimit"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/custom"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/deleteoauthv2info"
 	"github.com/kevinswiber/apigee-hcl/dsl/policies/extractvariables"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/flowcallout"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/generatesamlassertion"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/getoauthv2info"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/invalidatecache"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/javacallout"
 	"github.com/kevinswiber/apigee-hcl/dsl/policies/javascript"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/jsonthreatprotection"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/jsontoxml"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/keyvaluemapoperations"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/lookupcache"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/messagelogging"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/messagevalidation"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/monetizationlimitscheck"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/oauthv1"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/oauthv2"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/policy"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/populatecache"
 	"github.com/kevinswiber/apigee-hcl/dsl/policies/quota"
 	"github.com/kevinswiber/apigee-hcl/dsl/policies/raisefault"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/regularexpressionprotection"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/resetquota"
 	"github.com/kevinswiber/apigee-hcl/dsl/policies/responsecache"
 	"github.com/kevinswiber/apigee-hcl/dsl/policies/script"
 	"github.com/kevinswiber/apigee-hcl/dsl/policies/servicecallout"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/setoauthv2info"
 	"github.com/kevinswiber/apigee-hcl/dsl/policies/spikearrest"
 	"github.com/kevinswiber/apigee-hcl/dsl/policies/statisticscollector"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/validatesamlassertion"
 	"github.com/kevinswiber/apigee-hcl/dsl/policies/verifyapikey"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/xmlthreatprotection"
 	"github.com/kevinswiber/apigee-hcl/dsl/policies/xmltojson"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/xsltransform"
+	"reflect"
 )
 
 // PolicyList is a map of HCL policy types to policy factory functions.
 var PolicyList = map[string]func(*ast.ObjectItem) (interface{}, error){
-	"assign_message":       assignmessage.DecodeHCL,
-	"extract_variables":    extractvariables.DecodeHCL,
-	"javascript":           javascript.DecodeHCL,
-	"quota":                quota.DecodeHCL,
-	"raise_fault":          raisefault.DecodeHCL,
-	"response_cache":       responsecache.DecodeHCL,
-	"script":               script.DecodeHCL,
-	"service_callout":      servicecallout.DecodeHCL,
-	"spike_arrest":         spikearrest.DecodeHCL,
-	"statistics_collector": statisticscollector.DecodeHCL,
-	"verify_api_key":       verifyapikey.DecodeHCL,
-	"xml_to_json":          xmltojson.DecodeHCL,
+	"access_control":                accesscontrol.DecodeHCL,
+	"access_entity":                 accessentity.DecodeHCL,
+	"assign_message":                assignmessage.DecodeHCL,
+	"basic_authentication":          basicauthentication.DecodeHCL,
+	"concurrent_rate_limit":         concurrentratelimit.DecodeHCL,
+	"custom":                        custom.DecodeHCL,
+	"delete_oauth_v2_info":          deleteoauthv2info.DecodeHCL,
+	"extract_variables":             extractvariables.DecodeHCL,
+	"flow_callout":                  flowcallout.DecodeHCL,
+	"generate_saml_assertion":       generatesamlassertion.DecodeHCL,
+	"get_oauth_v2_info":             getoauthv2info.DecodeHCL,
+	"invalidate_cache":              invalidatecache.DecodeHCL,
+	"java_callout":                  javacallout.DecodeHCL,
+	"javascript":                    javascript.DecodeHCL,
+	"json_threat_protection":        jsonthreatprotection.DecodeHCL,
+	"json_to_xml":                   jsontoxml.DecodeHCL,
+	"key_value_map_operations":      keyvaluemapoperations.DecodeHCL,
+	"lookup_cache":                  lookupcache.DecodeHCL,
+	"message_logging":               messagelogging.DecodeHCL,
+	"message_validation":            messagevalidation.DecodeHCL,
+	"monetization_limits_check":     monetizationlimitscheck.DecodeHCL,
+	"oauth_v1":                      oauthv1.DecodeHCL,
+	"oauth_v2":                      oauthv2.DecodeHCL,
+	"populate_cache":                populatecache.DecodeHCL,
+	"quota":                         quota.DecodeHCL,
+	"raise_fault":                   raisefault.DecodeHCL,
+	"regular_expression_protection": regularexpressionprotection.DecodeHCL,
+	"reset_quota":                   resetquota.DecodeHCL,
+	"response_cache":                responsecache.DecodeHCL,
+	"script":                        script.DecodeHCL,
+	"service_callout":               servicecallout.DecodeHCL,
+	"set_oauth_v2_info":             setoauthv2info.DecodeHCL,
+	"spike_arrest":                  spikearrest.DecodeHCL,
+	"statistics_collector":          statisticscollector.DecodeHCL,
+	"validate_saml_assertion":       validatesamlassertion.DecodeHCL,
+	"verify_api_key":                verifyapikey.DecodeHCL,
+	"xml_threat_protection":         xmlthreatprotection.DecodeHCL,
+	"xml_to_json":                   xmltojson.DecodeHCL,
+	"xsl_transform":                 xsltransform.DecodeHCL,
+}
+
+// PolicyStructList is a map of HCL policy types to functions returning
+// an empty policy struct, used when decoding policies from XML.
+var PolicyStructList = map[string]func() policy.Namer{
+	"access_control":                func() policy.Namer { return &accesscontrol.AccessControl{} },
+	"access_entity":                 func() policy.Namer { return &accessentity.AccessEntity{} },
+	"assign_message":                func() policy.Namer { return &assignmessage.AssignMessage{} },
+	"basic_authentication":          func() policy.Namer { return &basicauthentication.BasicAuthentication{} },
+	"concurrent_rate_limit":         func() policy.Namer { return &concurrentratelimit.ConcurrentRateLimit{} },
+	"delete_oauth_v2_info":          func() policy.Namer { return &deleteoauthv2info.DeleteOAuthV2Info{} },
+	"extract_variables":             func() policy.Namer { return &extractvariables.ExtractVariables{} },
+	"flow_callout":                  func() policy.Namer { return &flowcallout.FlowCallout{} },
+	"generate_saml_assertion":       func() policy.Namer { return &generatesamlassertion.GenerateSAMLAssertion{} },
+	"get_oauth_v2_info":             func() policy.Namer { return &getoauthv2info.GetOAuthV2Info{} },
+	"invalidate_cache":              func() policy.Namer { return &invalidatecache.InvalidateCache{} },
+	"java_callout":                  func() policy.Namer { return &javacallout.JavaCallout{} },
+	"javascript":                    func() policy.Namer { return &javascript.JavaScript{} },
+	"json_threat_protection":        func() policy.Namer { return &jsonthreatprotection.JSONThreatProtection{} },
+	"json_to_xml":                   func() policy.Namer { return &jsontoxml.JSONToXML{} },
+	"key_value_map_operations":      func() policy.Namer { return &keyvaluemapoperations.KeyValueMapOperations{} },
+	"lookup_cache":                  func() policy.Namer { return &lookupcache.LookupCache{} },
+	"message_logging":               func() policy.Namer { return &messagelogging.MessageLogging{} },
+	"message_validation":            func() policy.Namer { return &messagevalidation.MessageValidation{} },
+	"monetization_limits_check":     func() policy.Namer { return &monetizationlimitscheck.MonetizationLimitsCheck{} },
+	"oauth_v1":                      func() policy.Namer { return &oauthv1.OAuthV1{} },
+	"oauth_v2":                      func() policy.Namer { return &oauthv2.OAuthV2{} },
+	"populate_cache":                func() policy.Namer { return &populatecache.PopulateCache{} },
+	"quota":                         func() policy.Namer { return &quota.Quota{} },
+	"raise_fault":                   func() policy.Namer { return &raisefault.RaiseFault{} },
+	"regular_expression_protection": func() policy.Namer { return &regularexpressionprotection.RegularExpressionProtection{} },
+	"reset_quota":                   func() policy.Namer { return &resetquota.ResetQuota{} },
+	"response_cache":                func() policy.Namer { return &responsecache.ResponseCache{} },
+	"script":                        func() policy.Namer { return &script.Script{} },
+	"service_callout":               func() policy.Namer { return &servicecallout.ServiceCallout{} },
+	"set_oauth_v2_info":             func() policy.Namer { return &setoauthv2info.SetOAuthV2Info{} },
+	"spike_arrest":                  func() policy.Namer { return &spikearrest.SpikeArrest{} },
+	"statistics_collector":          func() policy.Namer { return &statisticscollector.StatisticsCollector{} },
+	"validate_saml_assertion":       func() policy.Namer { return &validatesamlassertion.ValidateSAMLAssertion{} },
+	"verify_api_key":                func() policy.Namer { return &verifyapikey.VerifyAPIKey{} },
+	"xml_threat_protection":         func() policy.Namer { return &xmlthreatprotection.XMLThreatProtection{} },
+	"xml_to_json":                   func() policy.Namer { return &xmltojson.XMLToJSON{} },
+	"xsl_transform":                 func() policy.Namer { return &xsltransform.XSLTransform{} },
+}
+
+// PolicyFactory converts an HCL policy block into a policy.
+type PolicyFactory func(*ast.ObjectItem) (policy.Marshaler, error)
+
+// RegisterPolicy makes a policy type outside this package available to
+// HCL policy blocks as typeName. It is meant to be called from an init
+// function, before any Config is decoded, and returns an error when
+// typeName is already in use. A factory that returns a nil policy
+// without an error is reported as a decoding error.
+func RegisterPolicy(typeName string, factory PolicyFactory) error {
+	if typeName == "" || factory == nil {
+		return fmt.Errorf("policy registration requires a type name and a factory")
+	}
+
+	if _, ok := PolicyList[typeName]; ok {
+		return fmt.Errorf("policy type %q is already registered", typeName)
+	}
+
+	PolicyList[typeName] = func(item *ast.ObjectItem) (interface{}, error) {
+		p, err := factory(item)
+		if err != nil {
+			return nil, err
+		}
+
+		if v := reflect.ValueOf(p); !v.IsValid() || (v.Kind() == reflect.Ptr && v.IsNil()) {
+			return nil, &hclerror.PosError{
+				Pos: item.Pos(),
+				Err: fmt.Errorf("policy type %q returned no policy", typeName),
+			}
+		}
+
+		return p, nil
+	}
+
+	return nil
 }
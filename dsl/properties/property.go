@@ -1,6 +1,7 @@
 package properties
 
 import (
+	"encoding/xml"
 	"fmt"
 
 	"github.com/hashicorp/hcl"
@@ -36,3 +37,20 @@ func DecodeHCL(item *ast.ObjectItem) ([]*Property, error) {
 
 	return newProps, nil
 }
+
+// UnmarshalXML decodes a <Property/> element, keeping its value as a string.
+func (p *Property) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var v struct {
+		Name  string `xml:"name,attr"`
+		Value string `xml:",chardata"`
+	}
+
+	if err := d.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+
+	p.Name = v.Name
+	p.Value = v.Value
+
+	return nil
+}
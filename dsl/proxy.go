@@ -5,6 +5,7 @@ import (
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/hcl"
 	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/hashicorp/hcl/hcl/token"
 	"github.com/kevinswiber/apigee-hcl/dsl/hclerror"
 )
 
@@ -12,15 +13,26 @@ import (
 //
 // Documentation: http://docs.apigee.com/api-services/reference/api-proxy-configuration-reference#baseconfig
 type Proxy struct {
-	XMLName     string `xml:"APIProxy" hcl:"-"`
-	Name        string `xml:"name,attr,omitempty" hcl:"-"`
-	DisplayName string `xml:",omitempty" hcl:"display_name"`
-	Description string `xml:",omitempty" hcl:"description"`
+	XMLName     string    `xml:"APIProxy" hcl:"-"`
+	Name        string    `xml:"name,attr,omitempty" hcl:"-"`
+	DisplayName string    `xml:",omitempty" hcl:"display_name"`
+	Description string    `xml:",omitempty" hcl:"description"`
+	Pos         token.Pos `xml:"-" hcl:"-"`
 }
 
 func decodeProxyHCL(list *ast.ObjectList) (*Proxy, error) {
 	var errors *multierror.Error
 
+	if len(list.Items) > 1 {
+		pos := list.Items[1].Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("proxy may only be defined once"),
+		}
+		errors = multierror.Append(errors, &newError)
+		return nil, errors
+	}
+
 	var item = list.Items[0]
 	if len(item.Keys) == 0 || item.Keys[0].Token.Value() == "" {
 		pos := item.Val.Pos()
@@ -46,6 +58,7 @@ func decodeProxyHCL(list *ast.ObjectList) (*Proxy, error) {
 	}
 
 	proxy.Name = n
+	proxy.Pos = item.Pos()
 
 	return &proxy, nil
 }
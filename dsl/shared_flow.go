@@ -0,0 +1,84 @@
+package dsl
+
+import (
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/hashicorp/hcl/hcl/token"
+	"github.com/kevinswiber/apigee-hcl/dsl/endpoints"
+	"github.com/kevinswiber/apigee-hcl/dsl/hclerror"
+)
+
+// SharedFlowBundle represents a <SharedFlowBundle/> element in an Apigee
+// shared flow bundle. Its steps are kept in Flow, which is written to
+// sharedflows/default.xml.
+//
+// Documentation: http://docs.apigee.com/api-services/content/reusable-shared-flows
+type SharedFlowBundle struct {
+	XMLName     string      `xml:"SharedFlowBundle" hcl:"-"`
+	Name        string      `xml:"name,attr,omitempty" hcl:"-"`
+	DisplayName string      `xml:",omitempty" hcl:"display_name"`
+	Description string      `xml:",omitempty" hcl:"description"`
+	Flow        *SharedFlow `xml:"-" hcl:"-"`
+	Pos         token.Pos   `xml:"-" hcl:"-"`
+}
+
+// SharedFlow represents a <SharedFlow/> element in a shared flow bundle.
+//
+// Documentation: http://docs.apigee.com/api-services/content/reusable-shared-flows
+type SharedFlow struct {
+	XMLName string                `xml:"SharedFlow" hcl:"-"`
+	Name    string                `xml:"name,attr" hcl:"-"`
+	Steps   []*endpoints.FlowStep `xml:"Step" hcl:"step"`
+}
+
+func decodeSharedFlowHCL(list *ast.ObjectList) (*SharedFlowBundle, error) {
+	var errors *multierror.Error
+
+	if len(list.Items) > 1 {
+		pos := list.Items[1].Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("shared_flow may only be defined once"),
+		}
+		errors = multierror.Append(errors, &newError)
+		return nil, errors
+	}
+
+	var item = list.Items[0]
+	if len(item.Keys) == 0 || item.Keys[0].Token.Value() == "" {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("shared flow requires a name"),
+		}
+		errors = multierror.Append(errors, &newError)
+		return nil, errors
+	}
+
+	n := item.Keys[0].Token.Value().(string)
+
+	if _, ok := item.Val.(*ast.ObjectType); !ok {
+		errors = multierror.Append(errors, fmt.Errorf("shared flow not an object"))
+		return nil, errors
+	}
+
+	var sharedFlow SharedFlowBundle
+	if err := hcl.DecodeObject(&sharedFlow, item.Val); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	steps, err := endpoints.DecodeFlowStepsHCL(item)
+	if err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	sharedFlow.Name = n
+	sharedFlow.Pos = item.Pos()
+	sharedFlow.Flow = &SharedFlow{Name: "default", Steps: steps}
+
+	return &sharedFlow, nil
+}
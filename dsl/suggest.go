@@ -0,0 +1,83 @@
+package dsl
+
+import (
+	"sort"
+	"strings"
+)
+
+// suggestPolicyTypes returns the PolicyList types that are close to
+// the given type, ordered from closest to furthest.
+func suggestPolicyTypes(policyType string) []string {
+	maxDistance := len(policyType) / 3
+	if maxDistance < 2 {
+		maxDistance = 2
+	}
+
+	var candidates []candidate
+	for name := range PolicyList {
+		d := editDistance(policyType, name)
+		if d <= maxDistance ||
+			(len(policyType) > 2 && strings.HasPrefix(name, policyType)) {
+			candidates = append(candidates, candidate{name, d})
+		}
+	}
+
+	sort.Sort(byDistance(candidates))
+
+	var result []string
+	for _, c := range candidates {
+		result = append(result, c.name)
+	}
+
+	return result
+}
+
+type candidate struct {
+	name     string
+	distance int
+}
+
+type byDistance []candidate
+
+func (c byDistance) Len() int      { return len(c) }
+func (c byDistance) Swap(i, j int) { c[i], c[j] = c[j], c[i] }
+func (c byDistance) Less(i, j int) bool {
+	if c[i].distance != c[j].distance {
+		return c[i].distance < c[j].distance
+	}
+	return c[i].name < c[j].name
+}
+
+// editDistance returns the Levenshtein distance between two strings.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+
+	return prev[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
@@ -0,0 +1,204 @@
+package validate
+
+import (
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl/hcl/token"
+	"github.com/kevinswiber/apigee-hcl/dsl"
+	"github.com/kevinswiber/apigee-hcl/dsl/endpoints"
+	"github.com/kevinswiber/apigee-hcl/dsl/hclerror"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/messagelogging"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/policy"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/quota"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/resetquota"
+)
+
+// Config checks the cross references in a merged Config.
+//
+// Errors are returned for problems that would produce a broken bundle:
+// steps referring to undefined policies, route rules referring to
+// undefined target endpoints, reset quota policies referring to undefined
+// quota policies, and duplicate policy or endpoint names.
+// Warnings are returned for policies no step uses, for target endpoints
+// no route rule can reach, and for message logging steps outside a
+// post_client_flow or shared flow.
+func Config(c *dsl.Config) (warnings []error, errors error) {
+	var errs *multierror.Error
+
+	policies := make(map[string]token.Pos)
+	for _, p := range c.Policies {
+		pos := policyPos(p)
+		if first, ok := policies[p.Name()]; ok {
+			errs = multierror.Append(errs, duplicateError("policy", p.Name(), pos, first))
+			continue
+		}
+		policies[p.Name()] = pos
+	}
+
+	proxyEndpoints := make(map[string]token.Pos)
+	for _, e := range c.ProxyEndpoints {
+		if first, ok := proxyEndpoints[e.Name]; ok {
+			errs = multierror.Append(errs, duplicateError("proxy endpoint", e.Name, e.Pos, first))
+			continue
+		}
+		proxyEndpoints[e.Name] = e.Pos
+	}
+
+	targetEndpoints := make(map[string]token.Pos)
+	for _, e := range c.TargetEndpoints {
+		if first, ok := targetEndpoints[e.Name]; ok {
+			errs = multierror.Append(errs, duplicateError("target endpoint", e.Name, e.Pos, first))
+			continue
+		}
+		targetEndpoints[e.Name] = e.Pos
+	}
+
+	usedPolicies := make(map[string]bool)
+	for _, s := range c.Steps() {
+		usedPolicies[s.Name] = true
+		if _, ok := policies[s.Name]; !ok {
+			errs = multierror.Append(errs, &hclerror.PosError{
+				Pos: s.Pos,
+				Err: fmt.Errorf("step refers to undefined policy %q", s.Name),
+			})
+		}
+	}
+
+	for _, err := range resetQuotaErrors(c) {
+		errs = multierror.Append(errs, err)
+	}
+
+	warnings = append(warnings, messageLoggingWarnings(c)...)
+
+	usedTargets := make(map[string]bool)
+	for _, e := range c.ProxyEndpoints {
+		for _, r := range e.RouteRules {
+			if r.TargetEndpoint == "" {
+				continue
+			}
+
+			usedTargets[r.TargetEndpoint] = true
+			if _, ok := targetEndpoints[r.TargetEndpoint]; !ok {
+				errs = multierror.Append(errs, &hclerror.PosError{
+					Pos: r.Pos,
+					Err: fmt.Errorf("route rule %q refers to undefined target endpoint %q",
+						r.Name, r.TargetEndpoint),
+				})
+			}
+		}
+	}
+
+	for _, p := range c.Policies {
+		if !usedPolicies[p.Name()] {
+			warnings = append(warnings, &hclerror.PosError{
+				Pos: policyPos(p),
+				Err: fmt.Errorf("policy %q is not used by any step", p.Name()),
+			})
+		}
+	}
+
+	for _, e := range c.TargetEndpoints {
+		if !usedTargets[e.Name] {
+			warnings = append(warnings, &hclerror.PosError{
+				Pos: e.Pos,
+				Err: fmt.Errorf("target endpoint %q is not reachable from any route rule", e.Name),
+			})
+		}
+	}
+
+	if errs != nil {
+		return warnings, errs
+	}
+
+	return warnings, nil
+}
+
+// messageLoggingWarnings returns a warning for every step outside a
+// post_client_flow that runs a MessageLogging policy. Apigee recommends
+// logging from the PostClientFlow, after the response has been sent.
+func messageLoggingWarnings(c *dsl.Config) []error {
+	var warnings []error
+
+	loggers := make(map[string]bool)
+	for _, p := range c.Policies {
+		if _, ok := p.(*messagelogging.MessageLogging); ok {
+			loggers[p.Name()] = true
+		}
+	}
+
+	if len(loggers) == 0 {
+		return nil
+	}
+
+	postClientSteps := make(map[*endpoints.FlowStep]bool)
+	for _, e := range c.ProxyEndpoints {
+		if e.PostClientFlow != nil {
+			for _, s := range e.PostClientFlow.Response.Steps {
+				postClientSteps[s] = true
+			}
+		}
+	}
+
+	// Shared flows have no PostClientFlow, so their steps aren't checked.
+	sharedFlowSteps := make(map[*endpoints.FlowStep]bool)
+	if c.SharedFlow != nil && c.SharedFlow.Flow != nil {
+		for _, s := range c.SharedFlow.Flow.Steps {
+			sharedFlowSteps[s] = true
+		}
+	}
+
+	for _, s := range c.Steps() {
+		if loggers[s.Name] && !postClientSteps[s] && !sharedFlowSteps[s] {
+			warnings = append(warnings, &hclerror.PosError{
+				Pos: s.Pos,
+				Err: fmt.Errorf("message logging policy %q should run in a post_client_flow", s.Name),
+			})
+		}
+	}
+
+	return warnings
+}
+
+// resetQuotaErrors returns an error for every ResetQuota policy whose
+// quota does not name a Quota policy in the Config.
+func resetQuotaErrors(c *dsl.Config) []error {
+	var errors []error
+
+	quotas := make(map[string]bool)
+	for _, p := range c.Policies {
+		if _, ok := p.(*quota.Quota); ok {
+			quotas[p.Name()] = true
+		}
+	}
+
+	for _, p := range c.Policies {
+		r, ok := p.(*resetquota.ResetQuota)
+		if !ok || r.Quota == nil || quotas[r.Quota.Name] {
+			continue
+		}
+
+		errors = append(errors, &hclerror.PosError{
+			Pos: policyPos(p),
+			Err: fmt.Errorf("reset quota policy %q refers to undefined quota policy %q",
+				p.Name(), r.Quota.Name),
+		})
+	}
+
+	return errors
+}
+
+func policyPos(p policy.Namer) token.Pos {
+	if positioner, ok := p.(policy.Positioner); ok {
+		return positioner.Position()
+	}
+
+	return token.Pos{}
+}
+
+func duplicateError(kind, name string, pos, first token.Pos) error {
+	return &hclerror.PosError{
+		Pos: pos,
+		Err: fmt.Errorf("duplicate %s %q, first defined at %s, line %d",
+			kind, name, first.Filename, first.Line),
+	}
+}
@@ -0,0 +1,247 @@
+package validate
+
+import (
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/kevinswiber/apigee-hcl/dsl"
+	"strings"
+	"testing"
+)
+
+const testProxy = `
+proxy "p" {}
+
+proxy_endpoint "default" {
+  http_proxy_connection {
+    base_path = "/v0/p"
+  }
+
+  pre_flow {
+    request {
+      step "set-header" {}
+    }
+  }
+
+  route_rule "default" {
+    target_endpoint = "default"
+  }
+}
+
+target_endpoint "default" {
+  http_target_connection {
+    url = "http://example.com"
+  }
+}
+
+policy assign_message "set-header" {
+  set {
+    header "x-p" {
+      value = "1"
+    }
+  }
+}
+`
+
+const testLogger = `
+policy message_logging "log" {
+  syslog {
+    message = "{request.uri}"
+    host    = "logs.example.com"
+  }
+}
+`
+
+// decodeTestConfig decodes each source as its own file, a.hcl, b.hcl
+// and so on, and merges them.
+func decodeTestConfig(sources ...string) (*dsl.Config, error) {
+	var c dsl.Config
+
+	for i, src := range sources {
+		list, err := dsl.ParseHCL(fmt.Sprintf("%c.hcl", 'a'+i), []byte(src))
+		if err != nil {
+			return nil, err
+		}
+
+		cfg, err := dsl.DecodeConfigHCL(list)
+		if err != nil {
+			return nil, err
+		}
+
+		c.Merge(cfg)
+	}
+
+	return &c, nil
+}
+
+func TestConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		sources  []string
+		errors   []string
+		warnings []string
+	}{
+		{
+			name:    "valid",
+			sources: []string{testProxy},
+		},
+		{
+			name: "undefined policy",
+			sources: []string{testProxy, `
+proxy_endpoint "second" {
+  pre_flow {
+    request {
+      step "nope" {}
+    }
+  }
+}`},
+			errors: []string{`step refers to undefined policy "nope" (at b.hcl, line 5`},
+		},
+		{
+			name: "undefined target endpoint",
+			sources: []string{testProxy, `
+proxy_endpoint "second" {
+  route_rule "second" {
+    target_endpoint = "nope"
+  }
+}`},
+			errors: []string{`route rule "second" refers to undefined target endpoint "nope" (at b.hcl, line 3`},
+		},
+		{
+			name: "undefined quota",
+			sources: []string{testProxy, `
+proxy_endpoint "second" {
+  pre_flow {
+    request {
+      step "reset" {}
+    }
+  }
+}
+
+policy reset_quota "reset" {
+  quota "nope" {
+    identifier "_default" {
+      allow {
+        value = 1
+      }
+    }
+  }
+}`},
+			errors: []string{`reset quota policy "reset" refers to undefined quota policy "nope" (at b.hcl, line 10`},
+		},
+		{
+			name: "duplicate policy",
+			sources: []string{testProxy, `
+policy assign_message "set-header" {
+  remove {
+    header "x-p" {}
+  }
+}`},
+			errors: []string{`duplicate policy "set-header", first defined at a.hcl, line 26 (at b.hcl, line 2`},
+		},
+		{
+			name: "duplicate proxy endpoint",
+			sources: []string{testProxy, `
+proxy_endpoint "default" {
+  route_rule "default" {
+    target_endpoint = "default"
+  }
+}`},
+			errors: []string{`duplicate proxy endpoint "default", first defined at a.hcl, line 4 (at b.hcl, line 2`},
+		},
+		{
+			name: "duplicate target endpoint",
+			sources: []string{testProxy, `
+target_endpoint "default" {
+  http_target_connection {
+    url = "http://example.org"
+  }
+}`},
+			errors: []string{`duplicate target endpoint "default", first defined at a.hcl, line 20 (at b.hcl, line 2`},
+		},
+		{
+			name: "unused policy",
+			sources: []string{testProxy, `
+policy assign_message "unused" {
+  remove {
+    header "x-p" {}
+  }
+}`},
+			warnings: []string{`policy "unused" is not used by any step (at b.hcl, line 2`},
+		},
+		{
+			name: "unreachable target endpoint",
+			sources: []string{testProxy, `
+target_endpoint "other" {
+  http_target_connection {
+    url = "http://example.org"
+  }
+}`},
+			warnings: []string{`target endpoint "other" is not reachable from any route rule (at b.hcl, line 2`},
+		},
+		{
+			name: "message logging outside post_client_flow",
+			sources: []string{testProxy, testLogger, `
+proxy_endpoint "second" {
+  post_flow {
+    response {
+      step "log" {}
+    }
+  }
+}`},
+			warnings: []string{`message logging policy "log" should run in a post_client_flow (at c.hcl, line 5`},
+		},
+		{
+			name: "message logging in post_client_flow",
+			sources: []string{testProxy, testLogger, `
+proxy_endpoint "second" {
+  post_client_flow {
+    response {
+      step "log" {}
+    }
+  }
+}`},
+		},
+		{
+			name: "message logging in a shared flow",
+			sources: []string{testLogger, `
+shared_flow "s" {
+  step "log" {}
+}`},
+		},
+	}
+
+	for _, test := range tests {
+		c, err := decodeTestConfig(test.sources...)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+
+		warnings, err := Config(c)
+
+		var errs []error
+		if merr, ok := err.(*multierror.Error); ok {
+			errs = merr.Errors
+		} else if err != nil {
+			errs = []error{err}
+		}
+
+		checkMessages(t, test.name+": errors", errs, test.errors)
+		checkMessages(t, test.name+": warnings", warnings, test.warnings)
+	}
+}
+
+// checkMessages expects one error in errs for each of want, in order,
+// starting with it.
+func checkMessages(t *testing.T, name string, errs []error, want []string) {
+	if len(errs) != len(want) {
+		t.Errorf("%s = %q, want %q", name, errs, want)
+		return
+	}
+
+	for i, err := range errs {
+		if !strings.HasPrefix(err.Error(), want[i]) {
+			t.Errorf("%s[%d] = %q, want %q", name, i, err, want[i])
+		}
+	}
+}
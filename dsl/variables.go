@@ -0,0 +1,504 @@
+package dsl
+
+import (
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/hashicorp/hcl/hcl/token"
+	"github.com/kevinswiber/apigee-hcl/dsl/hclerror"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// referencePattern matches ${var.name} and ${local.name}. A reference
+// preceded by an extra "$" is escaped and left as literal text.
+var referencePattern = regexp.MustCompile(`(\$?)\$\{(var|local)\.([A-Za-z_][A-Za-z0-9_\-]*)\}`)
+
+// Inputs holds values for input variables, keyed by variable name.
+// Values are strings, int64s, float64s or bools.
+type Inputs map[string]interface{}
+
+// Set parses a name=value pair, as given to the -var flag, and stores
+// the value as a string.
+func (in Inputs) Set(arg string) error {
+	i := strings.Index(arg, "=")
+	if i <= 0 {
+		return fmt.Errorf("invalid variable %q, expected name=value", arg)
+	}
+
+	in[arg[:i]] = arg[i+1:]
+	return nil
+}
+
+// ParseVarFile reads variable values from an HCL or JSON file
+// containing name = value attributes.
+func ParseVarFile(filename string, src []byte) (Inputs, error) {
+	list, err := ParseHCL(filename, src)
+	if err != nil {
+		return nil, err
+	}
+
+	var errors *multierror.Error
+	in := make(Inputs)
+
+	for _, item := range list.Items {
+		lit, ok := item.Val.(*ast.LiteralType)
+		if len(item.Keys) != 1 || !ok {
+			errors = multierror.Append(errors, &hclerror.PosError{
+				Pos: item.Pos(),
+				Err: fmt.Errorf("variable values must be strings, numbers or booleans"),
+			})
+			continue
+		}
+
+		in[item.Keys[0].Token.Value().(string)] = lit.Token.Value()
+	}
+
+	if errors != nil {
+		return nil, errors
+	}
+
+	return in, nil
+}
+
+// Variables returns the sorted names of the variables declared in
+// lists. Invalid declarations are skipped; NewScope reports them.
+func Variables(lists []*ast.ObjectList) []string {
+	var names []string
+	for _, list := range lists {
+		for _, item := range list.Filter("variable").Items {
+			if len(item.Keys) == 0 {
+				continue
+			}
+
+			if name, ok := item.Keys[0].Token.Value().(string); ok && name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Scope holds the values of the variables and locals that can be
+// referenced from HCL attributes.
+type Scope struct {
+	vars   map[string]interface{}
+	locals map[string]interface{}
+
+	// localItems and resolving are used while resolving locals, which
+	// may refer to variables and to each other.
+	localItems map[string]*ast.ObjectItem
+	resolving  map[string]bool
+}
+
+// NewScope collects the top-level variable and locals blocks in lists
+// and resolves their values. Inputs override variable defaults; every
+// input must be declared by a variable block.
+//
+// Example:
+//
+//	variable "base_path" {
+//	  default = "/v0/hello"
+//	}
+//
+//	locals {
+//	  target_url = "https://${var.host}/api"
+//	}
+func NewScope(lists []*ast.ObjectList, inputs Inputs) (*Scope, error) {
+	var errors *multierror.Error
+
+	s := Scope{
+		vars:       make(map[string]interface{}),
+		locals:     make(map[string]interface{}),
+		localItems: make(map[string]*ast.ObjectItem),
+		resolving:  make(map[string]bool),
+	}
+
+	declared := make(map[string]*ast.ObjectItem)
+	for _, list := range lists {
+		for _, item := range list.Filter("variable").Items {
+			name, v, err := decodeVariableHCL(item)
+			if err != nil {
+				errors = multierror.Append(errors, err)
+				continue
+			}
+
+			if first, ok := declared[name]; ok {
+				errors = multierror.Append(errors, duplicateDefinitionError("variable", name, item, first))
+				continue
+			}
+			declared[name] = item
+
+			if input, ok := inputs[name]; ok {
+				input, err := convertInput(name, input, v)
+				if err != nil {
+					errors = multierror.Append(errors, err)
+					continue
+				}
+				v = input
+			}
+
+			if v == nil {
+				errors = multierror.Append(errors, &hclerror.PosError{
+					Pos: item.Pos(),
+					Err: fmt.Errorf("variable %q has no value, set it with -var or -var-file", name),
+				})
+				continue
+			}
+
+			s.vars[name] = v
+		}
+
+		for _, item := range list.Filter("locals").Items {
+			ot, ok := item.Val.(*ast.ObjectType)
+			if !ok {
+				errors = multierror.Append(errors, &hclerror.PosError{
+					Pos: item.Pos(),
+					Err: fmt.Errorf("locals is not an object"),
+				})
+				continue
+			}
+
+			for _, local := range ot.List.Items {
+				name := local.Keys[0].Token.Value().(string)
+				if first, ok := s.localItems[name]; ok {
+					errors = multierror.Append(errors, duplicateDefinitionError("local value", name, local, first))
+					continue
+				}
+				s.localItems[name] = local
+			}
+		}
+	}
+
+	var names []string
+	for name := range inputs {
+		if _, ok := declared[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	if len(names) > 0 {
+		sort.Strings(names)
+		for _, name := range names {
+			errors = multierror.Append(errors,
+				fmt.Errorf("variable %q was set but is not declared", name))
+		}
+	}
+
+	// Locals are only resolved once every variable has a value, so a
+	// missing variable isn't also reported as undefined.
+	if errors != nil {
+		return nil, errors
+	}
+
+	// A local that fails to resolve also fails every local that refers
+	// to it, so each error is only reported once.
+	names = nil
+	for name := range s.localItems {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	seen := make(map[string]bool)
+	for _, name := range names {
+		_, err := s.resolveLocal(name)
+		if err == nil {
+			continue
+		}
+
+		for _, e := range flattenErrors(err) {
+			if !seen[e.Error()] {
+				seen[e.Error()] = true
+				errors = multierror.Append(errors, e)
+			}
+		}
+	}
+
+	if errors != nil {
+		return nil, errors
+	}
+
+	return &s, nil
+}
+
+// Interpolate replaces variable and local references in every string
+// attribute in list, except within the variable and locals blocks
+// themselves. A string that consists of a single reference takes the
+// type of the referenced value, so numbers and booleans can be
+// interpolated into non-string attributes.
+func (s *Scope) Interpolate(list *ast.ObjectList) error {
+	var errors *multierror.Error
+
+	for _, item := range list.Items {
+		if len(item.Keys) > 0 {
+			switch item.Keys[0].Token.Value() {
+			case "variable", "locals":
+				continue
+			}
+		}
+
+		ast.Walk(item, func(n ast.Node) (ast.Node, bool) {
+			if lit, ok := n.(*ast.LiteralType); ok {
+				if err := s.interpolateLiteral(lit); err != nil {
+					errors = multierror.Append(errors, err)
+				}
+			}
+			return n, true
+		})
+	}
+
+	if errors != nil {
+		return errors
+	}
+
+	return nil
+}
+
+func (s *Scope) interpolateLiteral(lit *ast.LiteralType) error {
+	if lit.Token.Type != token.STRING && lit.Token.Type != token.HEREDOC {
+		return nil
+	}
+
+	src, ok := lit.Token.Value().(string)
+	if !ok || !strings.Contains(src, "${") {
+		return nil
+	}
+
+	v, err := s.interpolate(src, lit.Pos(), nil)
+	if err != nil {
+		return err
+	}
+
+	if str, ok := v.(string); ok && str == src {
+		return nil
+	}
+
+	lit.Token = valueToken(v, lit.Token.Pos)
+	return nil
+}
+
+// interpolate resolves the references in src. If src is exactly one
+// reference, the referenced value is returned with its own type.
+func (s *Scope) interpolate(src string, pos token.Pos, lookup func(string) (interface{}, error)) (interface{}, error) {
+	var errors *multierror.Error
+
+	matches := referencePattern.FindAllStringSubmatchIndex(src, -1)
+	if len(matches) == 0 {
+		return src, nil
+	}
+
+	resolve := func(m []int) (interface{}, error) {
+		kind, name := src[m[4]:m[5]], src[m[6]:m[7]]
+		var v interface{}
+		var ok bool
+		var err error
+
+		if kind == "var" {
+			v, ok = s.vars[name]
+		} else if lookup != nil {
+			v, err = lookup(name)
+			ok = err == nil
+		} else {
+			v, ok = s.locals[name]
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			kindName := "variable"
+			if kind == "local" {
+				kindName = "local value"
+			}
+			return nil, &hclerror.PosError{
+				Pos: pos,
+				Err: fmt.Errorf("undefined %s %q", kindName, name),
+			}
+		}
+
+		return v, nil
+	}
+
+	if m := matches[0]; len(matches) == 1 && m[0] == 0 && m[1] == len(src) && m[2] == m[3] {
+		return resolve(m)
+	}
+
+	var buf []byte
+	last := 0
+	for _, m := range matches {
+		buf = append(buf, src[last:m[0]]...)
+		last = m[1]
+
+		if m[2] != m[3] {
+			// Escaped: drop the leading "$" and keep the reference as text.
+			buf = append(buf, src[m[3]:m[1]]...)
+			continue
+		}
+
+		v, err := resolve(m)
+		if err != nil {
+			errors = multierror.Append(errors, err)
+			continue
+		}
+
+		buf = append(buf, fmt.Sprint(v)...)
+	}
+	buf = append(buf, src[last:]...)
+
+	if errors != nil {
+		return nil, errors
+	}
+
+	return string(buf), nil
+}
+
+func (s *Scope) resolveLocal(name string) (interface{}, error) {
+	if v, ok := s.locals[name]; ok {
+		return v, nil
+	}
+
+	item, ok := s.localItems[name]
+	if !ok {
+		return nil, fmt.Errorf("undefined local value %q", name)
+	}
+
+	if s.resolving[name] {
+		return nil, &hclerror.PosError{
+			Pos: item.Pos(),
+			Err: fmt.Errorf("local value %q refers to itself", name),
+		}
+	}
+	s.resolving[name] = true
+	defer delete(s.resolving, name)
+
+	lit, ok := item.Val.(*ast.LiteralType)
+	if !ok {
+		return nil, &hclerror.PosError{
+			Pos: item.Pos(),
+			Err: fmt.Errorf("local value %q must be a string, number or boolean", name),
+		}
+	}
+
+	v := lit.Token.Value()
+	if str, ok := v.(string); ok {
+		var err error
+		v, err = s.interpolate(str, lit.Pos(), func(ref string) (interface{}, error) {
+			if _, ok := s.localItems[ref]; !ok {
+				return nil, &hclerror.PosError{
+					Pos: lit.Pos(),
+					Err: fmt.Errorf("undefined local value %q", ref),
+				}
+			}
+			return s.resolveLocal(ref)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	s.locals[name] = v
+	return v, nil
+}
+
+func decodeVariableHCL(item *ast.ObjectItem) (string, interface{}, error) {
+	if len(item.Keys) == 0 || item.Keys[0].Token.Value() == "" {
+		return "", nil, &hclerror.PosError{
+			Pos: item.Pos(),
+			Err: fmt.Errorf("variable requires a name"),
+		}
+	}
+
+	name := item.Keys[0].Token.Value().(string)
+
+	ot, ok := item.Val.(*ast.ObjectType)
+	if !ok {
+		return "", nil, &hclerror.PosError{
+			Pos: item.Pos(),
+			Err: fmt.Errorf("variable %q is not an object", name),
+		}
+	}
+
+	defaults := ot.List.Filter("default")
+	if len(defaults.Items) == 0 {
+		return name, nil, nil
+	}
+
+	lit, ok := defaults.Items[0].Val.(*ast.LiteralType)
+	if !ok {
+		return "", nil, &hclerror.PosError{
+			Pos: defaults.Items[0].Pos(),
+			Err: fmt.Errorf("variable %q default must be a string, number or boolean", name),
+		}
+	}
+
+	return name, lit.Token.Value(), nil
+}
+
+// convertInput converts a string input, such as a -var flag, to the
+// type of the variable's default value.
+func convertInput(name string, input, def interface{}) (interface{}, error) {
+	str, ok := input.(string)
+	if !ok {
+		return input, nil
+	}
+
+	var err error
+	switch def.(type) {
+	case int64:
+		input, err = strconv.ParseInt(str, 0, 64)
+	case float64:
+		input, err = strconv.ParseFloat(str, 64)
+	case bool:
+		input, err = strconv.ParseBool(str)
+	}
+
+	if err != nil {
+		kind := "number"
+		if _, ok := def.(bool); ok {
+			kind = "boolean"
+		}
+		return nil, fmt.Errorf("variable %q: invalid value %q, expected a %s", name, str, kind)
+	}
+
+	return input, nil
+}
+
+// valueToken returns a literal token for a variable value.
+func valueToken(v interface{}, pos token.Pos) token.Token {
+	switch v := v.(type) {
+	case int64:
+		return token.Token{Type: token.NUMBER, Pos: pos, Text: strconv.FormatInt(v, 10)}
+	case float64:
+		return token.Token{Type: token.FLOAT, Pos: pos, Text: strconv.FormatFloat(v, 'f', -1, 64)}
+	case bool:
+		return token.Token{Type: token.BOOL, Pos: pos, Text: strconv.FormatBool(v)}
+	}
+
+	// JSON string tokens are unquoted with strconv.Unquote, which
+	// exactly reverses strconv.Quote.
+	return token.Token{Type: token.STRING, Pos: pos, Text: strconv.Quote(fmt.Sprint(v)), JSON: true}
+}
+
+func duplicateDefinitionError(kind, name string, item, first *ast.ObjectItem) error {
+	return &hclerror.PosError{
+		Pos: item.Pos(),
+		Err: fmt.Errorf("duplicate %s %q, first defined at %s, line %d",
+			kind, name, first.Pos().Filename, first.Pos().Line),
+	}
+}
+
+func flattenErrors(err error) []error {
+	if merr, ok := err.(*multierror.Error); ok {
+		var errs []error
+		for _, e := range merr.Errors {
+			errs = append(errs, flattenErrors(e)...)
+		}
+		return errs
+	}
+
+	return []error{err}
+}
@@ -0,0 +1,260 @@
+package dsl
+
+import (
+	"github.com/hashicorp/hcl/hcl/ast"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testDeclarations = `
+variable "name" {
+  default = "x"
+}
+
+variable "count" {
+  default = 3
+}
+
+variable "ratio" {
+  default = 1.5
+}
+
+variable "flag" {
+  default = true
+}
+
+locals {
+  url  = "https://${local.host}/v1"
+  host = "${var.name}"
+  port = 8080
+}
+`
+
+// interpolateTestHCL declares the test variables and locals, then
+// interpolates out = <value> and returns the result.
+func interpolateTestHCL(value string, inputs Inputs) (interface{}, error) {
+	list, err := ParseHCL("test.hcl", []byte(testDeclarations+"out = "+value+"\n"))
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := NewScope([]*ast.ObjectList{list}, inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.Interpolate(list); err != nil {
+		return nil, err
+	}
+
+	return list.Filter("out").Items[0].Val.(*ast.LiteralType).Token.Value(), nil
+}
+
+func TestInterpolate(t *testing.T) {
+	tests := []struct {
+		value  string
+		inputs Inputs
+		want   interface{}
+	}{
+		{`"${var.name}"`, nil, "x"},
+		{`"a-${var.name}-b"`, nil, "a-x-b"},
+		{`"${var.name}${var.name}"`, nil, "xx"},
+		{`"plain"`, nil, "plain"},
+		{`"${unknown}"`, nil, "${unknown}"},
+
+		// A single reference keeps the type of its value
+		{`"${var.count}"`, nil, int64(3)},
+		{`"${var.ratio}"`, nil, 1.5},
+		{`"${var.flag}"`, nil, true},
+		{`"n=${var.count}"`, nil, "n=3"},
+		{`"${local.port}"`, nil, int64(8080)},
+
+		// $${ escapes a reference
+		{`"$${var.name}"`, nil, "${var.name}"},
+		{`"$${var.name} is ${var.name}"`, nil, "${var.name} is x"},
+		{`"$${local.url}"`, nil, "${local.url}"},
+
+		// Locals may refer to variables and to each other in any order
+		{`"${local.url}"`, nil, "https://x/v1"},
+		{`"${local.host}"`, Inputs{"name": "y"}, "y"},
+
+		// String inputs are converted to the type of the default
+		{`"${var.name}"`, Inputs{"name": "z"}, "z"},
+		{`"${var.count}"`, Inputs{"count": "7"}, int64(7)},
+		{`"${var.count}"`, Inputs{"count": "0x10"}, int64(16)},
+		{`"${var.ratio}"`, Inputs{"ratio": "2.5"}, 2.5},
+		{`"${var.flag}"`, Inputs{"flag": "false"}, false},
+		{`"${var.count}"`, Inputs{"count": int64(9)}, int64(9)},
+	}
+
+	for _, test := range tests {
+		got, err := interpolateTestHCL(test.value, test.inputs)
+		if err != nil {
+			t.Errorf("%s with %v: %v", test.value, test.inputs, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s with %v = %#v, want %#v", test.value, test.inputs, got, test.want)
+		}
+	}
+}
+
+func TestInterpolateErrors(t *testing.T) {
+	tests := []struct {
+		value  string
+		inputs Inputs
+		want   string
+	}{
+		{`"${var.nope}"`, nil, `undefined variable "nope"`},
+		{`"${local.nope}"`, nil, `undefined local value "nope"`},
+		{`"${var.nope} and ${local.nope}"`, nil, `undefined local value "nope"`},
+		{`"x"`, Inputs{"nope": "1"}, `variable "nope" was set but is not declared`},
+		{`"x"`, Inputs{"count": "many"}, `variable "count": invalid value "many", expected a number`},
+		{`"x"`, Inputs{"ratio": "1.2.3"}, `variable "ratio": invalid value "1.2.3", expected a number`},
+		{`"x"`, Inputs{"flag": "maybe"}, `variable "flag": invalid value "maybe", expected a boolean`},
+	}
+
+	for _, test := range tests {
+		_, err := interpolateTestHCL(test.value, test.inputs)
+		if err == nil {
+			t.Errorf("%s with %v succeeded, want error %q", test.value, test.inputs, test.want)
+			continue
+		}
+
+		if !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s with %v: error = %q, want %q", test.value, test.inputs, err, test.want)
+		}
+	}
+}
+
+func TestNewScopeErrors(t *testing.T) {
+	tests := []struct {
+		src  string
+		want []string
+	}{
+		{
+			`variable "host" {}`,
+			[]string{`variable "host" has no value, set it with -var or -var-file`},
+		},
+		{
+			`variable "host" { default = ["a"] }`,
+			[]string{`variable "host" default must be a string, number or boolean`},
+		},
+		{
+			"variable \"host\" { default = \"a\" }\nvariable \"host\" { default = \"b\" }",
+			[]string{`duplicate variable "host", first defined at test.hcl, line 1`},
+		},
+		{
+			"locals { a = 1 }\nlocals { a = 2 }",
+			[]string{`duplicate local value "a", first defined at test.hcl, line 1`},
+		},
+		{
+			`locals { a = ["x"] }`,
+			[]string{`local value "a" must be a string, number or boolean`},
+		},
+		{
+			`locals { a = "${local.a}" }`,
+			[]string{`local value "a" refers to itself`},
+		},
+		{
+			`locals { a = "${local.b}", b = "${local.a}" }`,
+			[]string{`local value "a" refers to itself`, `local value "b" refers to itself`},
+		},
+		{
+			`locals { a = "${local.b}", b = "${local.c}", c = "${local.a}" }`,
+			[]string{
+				`local value "a" refers to itself`,
+				`local value "b" refers to itself`,
+				`local value "c" refers to itself`,
+			},
+		},
+		{
+			// The error in b is reported once, not again for a.
+			`locals { a = "${local.b}", b = "${var.nope}" }`,
+			[]string{`undefined variable "nope"`},
+		},
+	}
+
+	for _, test := range tests {
+		list, err := ParseHCL("test.hcl", []byte(test.src))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = NewScope([]*ast.ObjectList{list}, nil)
+		if err == nil {
+			t.Errorf("%s: succeeded, want errors %q", test.src, test.want)
+			continue
+		}
+
+		errs := flattenErrors(err)
+		if len(errs) != len(test.want) {
+			t.Errorf("%s: %d errors, want %d: %v", test.src, len(errs), len(test.want), err)
+		}
+
+		for _, want := range test.want {
+			found := false
+			for _, e := range errs {
+				if strings.Contains(e.Error(), want) {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("%s: errors = %q, want %q", test.src, err, want)
+			}
+		}
+	}
+}
+
+func TestInputsSet(t *testing.T) {
+	tests := []struct {
+		arg   string
+		name  string
+		value string
+		err   bool
+	}{
+		{arg: "a=b", name: "a", value: "b"},
+		{arg: "a=", name: "a", value: ""},
+		{arg: "a=b=c", name: "a", value: "b=c"},
+		{arg: "=b", err: true},
+		{arg: "ab", err: true},
+	}
+
+	for _, test := range tests {
+		in := make(Inputs)
+		err := in.Set(test.arg)
+		if test.err {
+			if err == nil {
+				t.Errorf("Set(%q) succeeded, want an error", test.arg)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("Set(%q): %v", test.arg, err)
+			continue
+		}
+
+		if v, ok := in[test.name]; !ok || v != test.value {
+			t.Errorf("Set(%q) = %v, want %s=%s", test.arg, in, test.name, test.value)
+		}
+	}
+}
+
+func TestParseVarFile(t *testing.T) {
+	in, err := ParseVarFile("vars.hcl", []byte("name = \"x\"\ncount = 2\nratio = 0.5\nflag = false\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Inputs{"name": "x", "count": int64(2), "ratio": 0.5, "flag": false}
+	if !reflect.DeepEqual(in, want) {
+		t.Errorf("ParseVarFile = %#v, want %#v", in, want)
+	}
+
+	if _, err := ParseVarFile("vars.hcl", []byte("name { a = 1 }\n")); err == nil {
+		t.Errorf("ParseVarFile accepted a block")
+	}
+}
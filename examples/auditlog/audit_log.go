@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/kevinswiber/apigee-hcl/dsl/hclerror"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/policy"
+)
+
+// defaultPort is the syslog port used when port is omitted.
+const defaultPort = 514
+
+// AuditLog is an audit_log policy block. It's rendered as a
+// <MessageLogging/> policy that sends one syslog line per request,
+// tagged with the event name.
+type AuditLog struct {
+	policy.Policy `hcl:",squash"`
+	DisplayName   string `hcl:"display_name"`
+	Event         string `hcl:"event"`
+	Host          string `hcl:"host"`
+	Port          int    `hcl:"port"`
+}
+
+// MarshalXML writes the policy as a <MessageLogging/> element.
+func (a *AuditLog) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type syslog struct {
+		Message string
+		Host    string
+		Port    int
+	}
+
+	v := struct {
+		XMLName         xml.Name `xml:"MessageLogging"`
+		Name            string   `xml:"name,attr"`
+		Enabled         bool     `xml:"enabled,attr"`
+		ContinueOnError bool     `xml:"continueOnError,attr,omitempty"`
+		Async           bool     `xml:"async,attr,omitempty"`
+		DisplayName     string   `xml:",omitempty"`
+		Syslog          syslog
+	}{
+		Name:            a.Name(),
+		Enabled:         a.Enabled,
+		ContinueOnError: a.ContinueOnError,
+		Async:           a.Async,
+		DisplayName:     a.DisplayName,
+		Syslog: syslog{
+			Message: fmt.Sprintf("audit event=%s proxy={apiproxy.name} verb={request.verb} "+
+				"uri={request.uri} status={response.status.code} client={client.ip}", a.Event),
+			Host: a.Host,
+			Port: a.Port,
+		},
+	}
+
+	return e.Encode(&v)
+}
+
+// decodeAuditLog converts an HCL ast.ObjectItem into an AuditLog object.
+func decodeAuditLog(item *ast.ObjectItem) (*AuditLog, error) {
+	var errors *multierror.Error
+	var p AuditLog
+
+	if _, ok := item.Val.(*ast.ObjectType); !ok {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("audit log policy not an object"),
+		}
+		return nil, &newError
+	}
+
+	if err := policy.DecodeHCL(item, &p.Policy); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	if err := hcl.DecodeObject(&p, item.Val.(*ast.ObjectType)); err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+
+	if p.Event == "" || p.Host == "" {
+		pos := item.Val.Pos()
+		newError := hclerror.PosError{
+			Pos: pos,
+			Err: fmt.Errorf("audit log requires event and host"),
+		}
+		errors = multierror.Append(errors, &newError)
+	}
+
+	if p.Port == 0 {
+		p.Port = defaultPort
+	}
+
+	if errors != nil {
+		return nil, errors
+	}
+
+	return &p, nil
+}
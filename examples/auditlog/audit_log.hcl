@@ -0,0 +1,30 @@
+proxy "AuditLogFixture" {}
+
+proxy_endpoint "default" {
+  http_proxy_connection {
+    base_path    = "/v0/audited"
+    virtual_host = ["default", "secure"]
+  }
+
+  route_rule "default" {
+    target_endpoint = "default"
+  }
+
+  post_client_flow {
+    response {
+      step "audit-orders" {}
+    }
+  }
+}
+
+target_endpoint "default" {
+  http_target_connection {
+    url = "http://mocktarget.apigee.net"
+  }
+}
+
+policy audit_log "audit-orders" {
+  display_name = "Audit Orders"
+  event        = "order-created"
+  host         = "audit.example.com"
+}
@@ -0,0 +1,53 @@
+// Command auditlog shows how to add an in-house policy type with
+// dsl.RegisterPolicy. It registers audit_log, then builds a proxy that
+// uses it:
+//
+//	policy audit_log "audit" {
+//	  event = "order-created"
+//	  host  = "audit.example.com"
+//	}
+package main
+
+import (
+	"flag"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/kevinswiber/apigee-hcl/bundle"
+	"github.com/kevinswiber/apigee-hcl/dsl"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/policy"
+	"io/ioutil"
+	"log"
+	"os"
+	"path"
+)
+
+func main() {
+	l := log.New(os.Stderr, "", 0)
+
+	input := flag.String("i", path.Join("examples", "auditlog", "audit_log.hcl"), "Optional. An HCL file to translate")
+	buildPath := flag.String("o", path.Join(".", "build"), "Optional. A build path")
+	flag.Parse()
+
+	err := dsl.RegisterPolicy("audit_log", func(item *ast.ObjectItem) (policy.Marshaler, error) {
+		return decodeAuditLog(item)
+	})
+	if err != nil {
+		l.Fatal(err)
+	}
+
+	src, err := ioutil.ReadFile(*input)
+	if err != nil {
+		l.Fatal(err)
+	}
+
+	b, err := bundle.Build(map[string][]byte{*input: src}, &bundle.Options{
+		ReadFile: ioutil.ReadFile,
+		ReadDir:  ioutil.ReadDir,
+	})
+	if err != nil {
+		l.Fatal(err)
+	}
+
+	if err := b.WriteDir(*buildPath); err != nil {
+		l.Fatal(err)
+	}
+}
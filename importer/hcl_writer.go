@@ -0,0 +1,490 @@
+package importer
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/hashicorp/hcl/hcl/printer"
+	"github.com/kevinswiber/apigee-hcl/dsl"
+	"github.com/kevinswiber/apigee-hcl/dsl/endpoints"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/custom"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/policy"
+	"io"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// WriteHCL writes a Config object as HCL source.
+//
+// Blocks and attributes are named after the hcl struct tags used when
+// decoding, so the output can be compiled back into an equivalent bundle.
+func WriteHCL(w io.Writer, c *dsl.Config) error {
+	var hw hclWriter
+
+	if c.Proxy != nil {
+		hw.block("proxy", reflect.ValueOf(c.Proxy).Elem())
+	}
+
+	for _, e := range c.ProxyEndpoints {
+		hw.proxyEndpoint(e)
+	}
+
+	for _, e := range c.TargetEndpoints {
+		hw.targetEndpoint(e)
+	}
+
+	for _, p := range c.Policies {
+		if err := hw.policy(p); err != nil {
+			return err
+		}
+	}
+
+	output, err := printer.Format(hw.buf.Bytes())
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(output)
+	return err
+}
+
+type hclWriter struct {
+	buf    bytes.Buffer
+	indent int
+}
+
+func (w *hclWriter) line(format string, args ...interface{}) {
+	w.buf.WriteString(strings.Repeat("  ", w.indent))
+	fmt.Fprintf(&w.buf, format, args...)
+	w.buf.WriteString("\n")
+}
+
+func (w *hclWriter) open(key string, labels ...string) {
+	if w.indent == 0 && w.buf.Len() > 0 {
+		w.buf.WriteString("\n")
+	}
+
+	header := key
+	for _, l := range labels {
+		header += " " + strconv.Quote(l)
+	}
+	w.line("%s {", header)
+	w.indent++
+}
+
+func (w *hclWriter) close() {
+	w.indent--
+	w.line("}")
+}
+
+func (w *hclWriter) attr(key string, value interface{}) {
+	switch v := value.(type) {
+	case string:
+		if strings.Contains(v, "\n") {
+			w.heredoc(key, v)
+			return
+		}
+		w.line("%s = %s", key, strconv.Quote(v))
+	case []string:
+		quoted := make([]string, len(v))
+		for i, s := range v {
+			quoted[i] = strconv.Quote(s)
+		}
+		w.line("%s = [%s]", key, strings.Join(quoted, ", "))
+	default:
+		w.line("%s = %v", key, v)
+	}
+}
+
+func (w *hclWriter) heredoc(key, content string) {
+	marker := "EOF"
+	for strings.Contains("\n"+content+"\n", "\n"+marker+"\n") {
+		marker += "F"
+	}
+
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+
+	w.line("%s = <<%s", key, marker)
+	w.buf.WriteString(content)
+	w.buf.WriteString(marker + "\n")
+}
+
+func (w *hclWriter) proxyEndpoint(e *endpoints.ProxyEndpoint) {
+	w.open("proxy_endpoint", e.Name)
+
+	if e.PreFlow != nil {
+		w.flow("pre_flow", "", "", "", e.PreFlow.Request.Steps, e.PreFlow.Response.Steps)
+	}
+
+	for _, f := range e.Flows {
+		w.flow("flow", f.Name, f.Description, f.Condition, f.Request.Steps, f.Response.Steps)
+	}
+
+	if e.PostFlow != nil {
+		w.flow("post_flow", "", "", "", e.PostFlow.Request.Steps, e.PostFlow.Response.Steps)
+	}
+
+	if e.PostClientFlow != nil {
+		w.flow("post_client_flow", "", "", "",
+			e.PostClientFlow.Request.Steps, e.PostClientFlow.Response.Steps)
+	}
+
+	w.faultRules(e.FaultRules, e.DefaultFaultRule)
+
+	if e.HTTPProxyConnection != nil {
+		w.block("http_proxy_connection", reflect.ValueOf(e.HTTPProxyConnection).Elem())
+	}
+
+	for _, r := range e.RouteRules {
+		w.block("route_rule", reflect.ValueOf(r).Elem())
+	}
+
+	w.close()
+}
+
+func (w *hclWriter) targetEndpoint(e *endpoints.TargetEndpoint) {
+	w.open("target_endpoint", e.Name)
+
+	if e.PreFlow != nil {
+		w.flow("pre_flow", "", "", "", e.PreFlow.Request.Steps, e.PreFlow.Response.Steps)
+	}
+
+	for _, f := range e.Flows {
+		w.flow("flow", f.Name, f.Description, f.Condition, f.Request.Steps, f.Response.Steps)
+	}
+
+	if e.PostFlow != nil {
+		w.flow("post_flow", "", "", "", e.PostFlow.Request.Steps, e.PostFlow.Response.Steps)
+	}
+
+	if e.EventFlow != nil {
+		if e.EventFlow.Name != "" {
+			w.open("event_flow", e.EventFlow.Name)
+		} else {
+			w.open("event_flow")
+		}
+		if e.EventFlow.ContentType != "" {
+			w.attr("content_type", e.EventFlow.ContentType)
+		}
+		if len(e.EventFlow.Response.Steps) > 0 {
+			w.open("response")
+			w.steps(e.EventFlow.Response.Steps)
+			w.close()
+		}
+		w.close()
+	}
+
+	w.faultRules(e.FaultRules, e.DefaultFaultRule)
+
+	if e.HTTPTargetConnection != nil {
+		w.block("http_target_connection", reflect.ValueOf(e.HTTPTargetConnection).Elem())
+	}
+
+	if e.LocalTargetConnection != nil {
+		w.block("local_target_connection", reflect.ValueOf(e.LocalTargetConnection).Elem())
+	}
+
+	if e.ScriptTarget != nil {
+		w.block("script_target", reflect.ValueOf(e.ScriptTarget).Elem())
+	}
+
+	if e.SSLInfo != nil {
+		w.block("ssl_info", reflect.ValueOf(e.SSLInfo).Elem())
+	}
+
+	w.close()
+}
+
+func (w *hclWriter) flow(key, name, description, condition string, request, response []*endpoints.FlowStep) {
+	if name != "" {
+		w.open(key, name)
+	} else {
+		w.open(key)
+	}
+
+	if description != "" {
+		w.attr("description", description)
+	}
+
+	if condition != "" {
+		w.attr("condition", condition)
+	}
+
+	if len(request) > 0 {
+		w.open("request")
+		w.steps(request)
+		w.close()
+	}
+
+	if len(response) > 0 {
+		w.open("response")
+		w.steps(response)
+		w.close()
+	}
+
+	w.close()
+}
+
+func (w *hclWriter) faultRules(rules []*endpoints.FaultRule, defaultRule *endpoints.DefaultFaultRule) {
+	for _, r := range rules {
+		w.open("fault_rule", r.Name)
+		if r.Condition != "" {
+			w.attr("condition", r.Condition)
+		}
+		w.steps(r.Steps)
+		w.close()
+	}
+
+	if defaultRule != nil {
+		w.open("default_fault_rule", defaultRule.Name)
+		if defaultRule.Condition != "" {
+			w.attr("condition", defaultRule.Condition)
+		}
+		if defaultRule.AlwaysEnforce {
+			w.attr("always_enforce", true)
+		}
+		w.steps(defaultRule.Steps)
+		w.close()
+	}
+}
+
+func (w *hclWriter) steps(steps []*endpoints.FlowStep) {
+	for _, s := range steps {
+		if s.Condition == "" {
+			w.line("step %s {}", strconv.Quote(s.Name))
+			continue
+		}
+
+		w.open("step", s.Name)
+		w.attr("condition", s.Condition)
+		w.close()
+	}
+}
+
+func (w *hclWriter) policy(p policy.Namer) error {
+	v := reflect.ValueOf(p).Elem()
+
+	c, isCustom := p.(*custom.Custom)
+
+	policyType := ""
+	if isCustom {
+		policyType = "custom"
+	} else {
+		for t, f := range dsl.PolicyStructList {
+			if reflect.TypeOf(f()).Elem() == v.Type() {
+				policyType = t
+				break
+			}
+		}
+	}
+
+	if policyType == "" {
+		return fmt.Errorf("unsupported policy %s", p.Name())
+	}
+
+	w.open("policy "+policyType, p.Name())
+
+	base := v.FieldByName("Policy").Interface().(policy.Policy)
+	if !base.Enabled {
+		w.attr("enabled", false)
+	}
+	if base.ContinueOnError {
+		w.attr("continue_on_error", true)
+	}
+	if base.Async {
+		w.attr("async", true)
+	}
+
+	if isCustom {
+		w.customBody(c)
+	} else {
+		w.fields(v)
+	}
+	w.close()
+
+	return nil
+}
+
+// customBody writes the element and body of a custom policy.
+func (w *hclWriter) customBody(c *custom.Custom) {
+	w.attr("element", c.Element)
+
+	if len(c.Attrs) == 0 && len(c.Body) == 0 {
+		return
+	}
+
+	w.open("body")
+	for _, a := range c.Attrs {
+		w.attr(strconv.Quote("@"+a.Name.Local), a.Value)
+	}
+	for _, n := range c.Body {
+		w.node(n)
+	}
+	w.close()
+}
+
+// identifier matches the keys HCL accepts without quotes.
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+
+// node writes an element of a custom policy's body. Text-only elements
+// are written as attributes, all others as blocks.
+func (w *hclWriter) node(n *custom.Node) {
+	key := n.Name
+	if !identifier.MatchString(key) {
+		key = strconv.Quote(key)
+	}
+
+	if len(n.Attrs) == 0 && len(n.Children) == 0 {
+		if n.Text == "" {
+			w.line("%s {}", key)
+		} else {
+			w.attr(key, n.Text)
+		}
+		return
+	}
+
+	w.open(key)
+	for _, a := range n.Attrs {
+		w.attr(strconv.Quote("@"+a.Name.Local), a.Value)
+	}
+	if n.Text != "" {
+		w.attr(strconv.Quote("#text"), n.Text)
+	}
+	for _, c := range n.Children {
+		w.node(c)
+	}
+	w.close()
+}
+
+// block writes a struct as an HCL block. A Name or Prefix field that is
+// excluded from HCL decoding is taken from the block key instead.
+func (w *hclWriter) block(key string, v reflect.Value) {
+	if label := blockLabel(v); label != "" {
+		w.open(key, label)
+	} else {
+		w.open(key)
+	}
+	w.fields(v)
+	w.close()
+}
+
+func (w *hclWriter) fields(v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := strings.Split(f.Tag.Get("hcl"), ",")
+		if tag[0] == "-" || f.PkgPath != "" {
+			continue
+		}
+
+		if f.Anonymous {
+			// The base policy attributes are written by policy().
+			if f.Type != reflect.TypeOf(policy.Policy{}) {
+				w.fields(v.Field(i))
+			}
+			continue
+		}
+
+		key := tag[0]
+		if key == "" {
+			key = f.Name
+		}
+
+		w.value(key, v.Field(i), f)
+	}
+}
+
+func (w *hclWriter) value(key string, v reflect.Value, f reflect.StructField) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			w.value(key, v.Elem(), f)
+		}
+	case reflect.String:
+		if v.String() != "" {
+			w.attr(key, v.String())
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Int() != 0 {
+			w.attr(key, v.Int())
+		}
+	case reflect.Float32, reflect.Float64:
+		if v.Float() != 0 {
+			w.attr(key, v.Float())
+		}
+	case reflect.Bool:
+		// Booleans that are always present in the XML are always written,
+		// so decoding defaults can't flip them.
+		if v.Bool() || !strings.Contains(f.Tag.Get("xml"), "omitempty") {
+			w.attr(key, v.Bool())
+		}
+	case reflect.Struct:
+		w.block(key, v)
+	case reflect.Slice:
+		w.slice(key, v)
+	}
+}
+
+func (w *hclWriter) slice(key string, v reflect.Value) {
+	if v.Len() == 0 {
+		return
+	}
+
+	elemType := v.Type().Elem()
+	if elemType.Kind() == reflect.String {
+		w.attr(key, v.Interface())
+		return
+	}
+
+	if elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+
+	if elemType.Kind() != reflect.Struct {
+		return
+	}
+
+	if keyField, ok := mapKeyField(elemType); ok {
+		w.open(key)
+		for i := 0; i < v.Len(); i++ {
+			elem := reflect.Indirect(v.Index(i))
+			w.attr(strconv.Quote(elem.Field(keyField).String()),
+				fmt.Sprint(elem.FieldByName("Value").Interface()))
+		}
+		w.close()
+		return
+	}
+
+	for i := 0; i < v.Len(); i++ {
+		w.value(key, v.Index(i), reflect.StructField{})
+	}
+}
+
+// mapKeyField returns the index of a field tagged with `hcl:",key"`.
+// Such structs (e.g. properties) are written as a single block of
+// key/value attributes.
+func mapKeyField(t reflect.Type) (int, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Tag.Get("hcl") == ",key" {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+func blockLabel(v reflect.Value) string {
+	for _, name := range []string{"Name", "Prefix"} {
+		f, ok := v.Type().FieldByName(name)
+		if !ok || f.Tag.Get("hcl") != "-" || len(f.Index) > 1 {
+			continue
+		}
+
+		return v.FieldByIndex(f.Index).String()
+	}
+
+	return ""
+}
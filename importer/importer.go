@@ -0,0 +1,392 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/kevinswiber/apigee-hcl/dsl"
+	"github.com/kevinswiber/apigee-hcl/dsl/endpoints"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/custom"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/javascript"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/messagevalidation"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/policy"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/script"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/xsltransform"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ReadBundle converts an exported Apigee proxy bundle into a Config object.
+//
+// The bundle path may point at either the apiproxy directory itself or at
+// the directory containing it. Resource files are collected into
+// Config.Resources, keyed by their resource URL (e.g. jsc://file.js).
+// JavaScript and Python resources referenced by a policy are inlined
+// as policy content instead.
+func ReadBundle(bundlePath string) (*dsl.Config, error) {
+	var errors *multierror.Error
+	var c dsl.Config
+
+	apiProxyPath := path.Join(bundlePath, "apiproxy")
+	if stat, err := os.Stat(apiProxyPath); err != nil || !stat.IsDir() {
+		apiProxyPath = bundlePath
+	}
+
+	proxy, err := readProxyXML(apiProxyPath)
+	if err != nil {
+		errors = multierror.Append(errors, err)
+		return nil, errors
+	}
+	c.Proxy = proxy
+
+	err = eachXMLFile(path.Join(apiProxyPath, "proxies"), func(file string, data []byte) error {
+		var e endpoints.ProxyEndpoint
+		if err := xml.Unmarshal(data, &e); err != nil {
+			return err
+		}
+		if err := checkUnmapped(data, &e); err != nil {
+			return err
+		}
+		c.ProxyEndpoints = append(c.ProxyEndpoints, &e)
+		return nil
+	})
+	if err != nil {
+		errors = multierror.Append(errors, err)
+	}
+
+	err = eachXMLFile(path.Join(apiProxyPath, "targets"), func(file string, data []byte) error {
+		var e endpoints.TargetEndpoint
+		if err := xml.Unmarshal(data, &e); err != nil {
+			return err
+		}
+		if err := checkUnmapped(data, &e); err != nil {
+			return err
+		}
+		c.TargetEndpoints = append(c.TargetEndpoints, &e)
+		return nil
+	})
+	if err != nil {
+		errors = multierror.Append(errors, err)
+	}
+
+	err = eachXMLFile(path.Join(apiProxyPath, "policies"), func(file string, data []byte) error {
+		p, err := decodePolicyXML(data)
+		if err != nil {
+			return err
+		}
+		if p.Name() == "" {
+			p.SetName(strings.TrimSuffix(path.Base(file), ".xml"))
+		}
+		c.Policies = append(c.Policies, p)
+		return nil
+	})
+	if err != nil {
+		errors = multierror.Append(errors, err)
+	}
+
+	resources, err := readResources(path.Join(apiProxyPath, "resources"))
+	if err != nil {
+		errors = multierror.Append(errors, err)
+	}
+
+	for _, p := range c.Policies {
+		switch p := p.(type) {
+		case *javascript.JavaScript:
+			if content, ok := resources[p.ResourceURL]; ok {
+				p.Content = string(content)
+				delete(resources, p.ResourceURL)
+			}
+		case *script.Script:
+			if content, ok := resources[p.ResourceURL]; ok {
+				p.Content = string(content)
+				delete(resources, p.ResourceURL)
+			}
+		case *xsltransform.XSLTransform:
+			if content, ok := resources[p.ResourceURL]; ok {
+				p.Content = string(content)
+				delete(resources, p.ResourceURL)
+			}
+		case *messagevalidation.MessageValidation:
+			if content, ok := resources[p.ResourceURL]; ok {
+				p.Content = string(content)
+				delete(resources, p.ResourceURL)
+			}
+		}
+	}
+
+	if len(resources) > 0 {
+		c.Resources = resources
+	}
+
+	if errors != nil {
+		return nil, errors
+	}
+
+	return &c, nil
+}
+
+func readProxyXML(apiProxyPath string) (*dsl.Proxy, error) {
+	files, err := ioutil.ReadDir(apiProxyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range files {
+		if f.IsDir() || path.Ext(f.Name()) != ".xml" {
+			continue
+		}
+
+		file := path.Join(apiProxyPath, f.Name())
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		var proxy dsl.Proxy
+		if err := xml.Unmarshal(data, &proxy); err != nil {
+			return nil, fmt.Errorf("%s: %v", file, err)
+		}
+
+		if proxy.Name == "" {
+			proxy.Name = strings.TrimSuffix(f.Name(), ".xml")
+		}
+
+		return &proxy, nil
+	}
+
+	return nil, fmt.Errorf("no proxy definition found in %s", apiProxyPath)
+}
+
+// eachXMLFile calls fn with the contents of every XML file in dir.
+// A missing directory is not an error.
+func eachXMLFile(dir string, fn func(string, []byte) error) error {
+	var errors *multierror.Error
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	for _, f := range files {
+		if f.IsDir() || path.Ext(f.Name()) != ".xml" {
+			continue
+		}
+
+		file := path.Join(dir, f.Name())
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			errors = multierror.Append(errors, err)
+			continue
+		}
+
+		if err := fn(file, data); err != nil {
+			errors = multierror.Append(errors, fmt.Errorf("%s: %v", file, err))
+		}
+	}
+
+	if errors != nil {
+		return errors
+	}
+
+	return nil
+}
+
+func readResources(dir string) (map[string][]byte, error) {
+	resources := make(map[string][]byte)
+
+	langs, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return resources, nil
+		}
+		return nil, err
+	}
+
+	for _, lang := range langs {
+		if !lang.IsDir() {
+			continue
+		}
+
+		files, err := ioutil.ReadDir(path.Join(dir, lang.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, f := range files {
+			if f.IsDir() {
+				continue
+			}
+
+			data, err := ioutil.ReadFile(path.Join(dir, lang.Name(), f.Name()))
+			if err != nil {
+				return nil, err
+			}
+
+			resources[lang.Name()+"://"+f.Name()] = data
+		}
+	}
+
+	return resources, nil
+}
+
+// decodePolicyXML looks up the policy type by its root element and
+// unmarshals the XML into the matching policy struct.
+func decodePolicyXML(data []byte) (policy.Namer, error) {
+	root, err := rootElement(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Policies without an HCL type of their own are kept as custom policies.
+	policyType, ok := policyTypeOf(root)
+	if !ok {
+		return custom.DecodeXML(data)
+	}
+
+	p := dsl.PolicyStructList[policyType]()
+
+	// Apigee treats a missing enabled attribute as enabled.
+	if enabled := reflect.ValueOf(p).Elem().FieldByName("Enabled"); enabled.IsValid() {
+		enabled.SetBool(true)
+	}
+
+	if err := xml.Unmarshal(data, p); err != nil {
+		return nil, err
+	}
+
+	if err := checkUnmapped(data, p); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// checkUnmapped returns an error naming the elements of data that
+// were lost when it was unmarshaled into v, found by marshaling v
+// again. Empty elements carry nothing to lose and are ignored.
+func checkUnmapped(data []byte, v interface{}) error {
+	out, err := xml.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	in, err := elementPaths(data, false)
+	if err != nil {
+		return err
+	}
+
+	mapped, err := elementPaths(out, true)
+	if err != nil {
+		return err
+	}
+
+	var missing []string
+	for p := range in {
+		if mapped[p] {
+			continue
+		}
+
+		// Report an element once, not once for each of its children.
+		if parent := path.Dir(p); parent == "." || mapped[parent] {
+			missing = append(missing, p)
+		}
+	}
+	sort.Strings(missing)
+
+	if len(missing) > 0 {
+		return fmt.Errorf("can't import %s, no HCL attribute maps to it", strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// elementPaths returns the set of paths of the elements in data, e.g.
+// Quota/Allow/Class. Unless all is true, only elements with
+// attributes, text or such children are included.
+func elementPaths(data []byte, all bool) (map[string]bool, error) {
+	type element struct {
+		path       string
+		hasContent bool
+	}
+
+	paths := make(map[string]bool)
+	var stack []*element
+
+	d := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := t.(type) {
+		case xml.StartElement:
+			p := t.Name.Local
+			if len(stack) > 0 {
+				p = stack[len(stack)-1].path + "/" + p
+			}
+			stack = append(stack, &element{path: p, hasContent: all || len(t.Attr) > 0})
+		case xml.CharData:
+			if len(stack) > 0 && len(bytes.TrimSpace(t)) > 0 {
+				stack[len(stack)-1].hasContent = true
+			}
+		case xml.EndElement:
+			e := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if !e.hasContent {
+				continue
+			}
+
+			if len(stack) > 0 {
+				stack[len(stack)-1].hasContent = true
+			}
+			paths[e.path] = true
+		}
+	}
+
+	return paths, nil
+}
+
+func rootElement(data []byte) (string, error) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			return "", fmt.Errorf("no root element found")
+		}
+		if err != nil {
+			return "", err
+		}
+
+		if start, ok := t.(xml.StartElement); ok {
+			return start.Name.Local, nil
+		}
+	}
+}
+
+// policyTypeOf returns the HCL policy type whose struct marshals
+// to the given XML element name.
+func policyTypeOf(element string) (string, bool) {
+	for policyType, f := range dsl.PolicyStructList {
+		t := reflect.TypeOf(f()).Elem()
+		if field, ok := t.FieldByName("XMLName"); ok {
+			if field.Tag.Get("xml") == element {
+				return policyType, true
+			}
+		}
+	}
+
+	return "", false
+}
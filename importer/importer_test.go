@@ -0,0 +1,86 @@
+package importer
+
+import (
+	"bytes"
+	"github.com/kevinswiber/apigee-hcl/bundle"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestRoundTrip builds each fixture, imports the bundle and builds the
+// imported HCL again, and expects the same files both times. Fixtures
+// that don't build on their own, e.g. invalid ones, are skipped.
+func TestRoundTrip(t *testing.T) {
+	fixtures, err := filepath.Glob(filepath.Join("..", "test-fixtures", "*.hcl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts := &bundle.Options{ReadFile: ioutil.ReadFile, ReadDir: ioutil.ReadDir}
+
+	for _, fixture := range fixtures {
+		src, err := ioutil.ReadFile(fixture)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want, err := bundle.Build(map[string][]byte{fixture: src}, opts)
+		if err != nil || want.Dir != "apiproxy" {
+			t.Logf("%s: skipped", fixture)
+			continue
+		}
+
+		dir, err := ioutil.TempDir("", "apigee-hcl")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+
+		if err := want.WriteDir(dir); err != nil {
+			t.Fatal(err)
+		}
+
+		c, err := ReadBundle(dir)
+		if err != nil {
+			t.Errorf("%s: import: %v", fixture, err)
+			continue
+		}
+
+		var buf bytes.Buffer
+		if err := WriteHCL(&buf, c); err != nil {
+			t.Errorf("%s: write HCL: %v", fixture, err)
+			continue
+		}
+
+		got, err := bundle.Build(map[string][]byte{"imported.hcl": buf.Bytes()}, opts)
+		if err != nil {
+			t.Errorf("%s: build imported HCL: %v\n%s", fixture, err, buf.String())
+			continue
+		}
+
+		// Resources the imported HCL doesn't inline are written next to
+		// it by the import command, then added with the resources path.
+		for url, content := range c.Resources {
+			parts := strings.SplitN(url, "://", 2)
+			got.Files[path.Join(got.Dir, "resources", parts[0], parts[1])] = content
+		}
+
+		for _, p := range want.Paths() {
+			if g, ok := got.Files[p]; !ok {
+				t.Errorf("%s: %s missing after import", fixture, p)
+			} else if !bytes.Equal(g, want.Files[p]) {
+				t.Errorf("%s: %s differs after import:\n%s\nwant:\n%s", fixture, p, g, want.Files[p])
+			}
+		}
+
+		for _, p := range got.Paths() {
+			if _, ok := want.Files[p]; !ok {
+				t.Errorf("%s: %s added by import", fixture, p)
+			}
+		}
+	}
+}
@@ -3,15 +3,33 @@ package main
 import (
 	"flag"
 	"github.com/kevinswiber/apigee-hcl/cli"
+	"log"
+	"os"
 	"path"
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "import":
+			runImport(os.Args[2:])
+			return
+		case "simulate":
+			runSimulate(os.Args[2:])
+			return
+		}
+	}
+
 	var options cli.Options
 
-	flag.Var(&options.InputHCL, "i", "Required. An HCL file to translate")
+	flag.Var(&options.InputHCL, "i", "Required. An HCL file or workspace directory to translate")
 	flag.StringVar(&options.BuildPath, "o", path.Join(".", "build"), "Optional. A build path")
 	flag.StringVar(&options.ResourcesPath, "r", path.Join(".", "resources"), "Optional. A path to resources")
+	flag.BoolVar(&options.Zip, "z", false, "Optional. Write a <proxy>.zip bundle to the build path")
+	flag.Var(&options.Vars, "var", "Optional. Set a variable, as name=value")
+	flag.Var(&options.VarFiles, "var-file", "Optional. An HCL or JSON file of variable values")
+	flag.StringVar(&options.Environment, "env", "", "Optional. An environment overlay to apply")
+	flag.BoolVar(&options.AllEnvs, "all-envs", false, "Optional. Build each environment into <build path>/<env>")
 	flag.Parse()
 
 	if len(options.InputHCL) == 0 {
@@ -19,5 +37,47 @@ func main() {
 		return
 	}
 
-	cli.Start(&options)
+	if err := cli.Start(&options); err != nil {
+		log.New(os.Stderr, "", 0).Fatal(err)
+	}
+}
+
+func runImport(args []string) {
+	var options cli.ImportOptions
+
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	flags.StringVar(&options.BundlePath, "i", "", "Required. An exported proxy bundle directory")
+	flags.StringVar(&options.OutputHCL, "o", "", "Optional. An HCL file to write (defaults to stdout)")
+	flags.StringVar(&options.ResourcesPath, "r", path.Join(".", "resources"), "Optional. A path to write resources")
+	flags.Parse(args)
+
+	if options.BundlePath == "" {
+		flags.Usage()
+		return
+	}
+
+	if err := cli.Import(&options); err != nil {
+		log.New(os.Stderr, "", 0).Fatal(err)
+	}
+}
+
+func runSimulate(args []string) {
+	var options cli.SimulateOptions
+
+	flags := flag.NewFlagSet("simulate", flag.ExitOnError)
+	flags.Var(&options.InputHCL, "i", "Required. An HCL file describing the proxy")
+	flags.StringVar(&options.Request, "request", "", "Required. An HCL or JSON file describing the request")
+	flags.Var(&options.Vars, "var", "Optional. Set a variable, as name=value")
+	flags.Var(&options.VarFiles, "var-file", "Optional. An HCL or JSON file of variable values")
+	flags.StringVar(&options.Environment, "env", "", "Optional. An environment overlay to apply")
+	flags.Parse(args)
+
+	if len(options.InputHCL) == 0 || options.Request == "" {
+		flags.Usage()
+		return
+	}
+
+	if err := cli.Simulate(&options); err != nil {
+		log.New(os.Stderr, "", 0).Fatal(err)
+	}
 }
@@ -0,0 +1,90 @@
+package simulate
+
+import (
+	"fmt"
+	"github.com/hashicorp/hcl"
+	"github.com/kevinswiber/apigee-hcl/dsl"
+	"net/url"
+	"strings"
+)
+
+// Request describes the client request to simulate.
+//
+// Example:
+//
+//	verb = "GET"
+//	path = "/v0/hello/cats?limit=10"
+//
+//	headers {
+//	  Accept = "application/json"
+//	}
+type Request struct {
+	Verb    string            `hcl:"verb"`
+	Path    string            `hcl:"path"`
+	Headers map[string]string `hcl:"headers"`
+	Query   map[string]string `hcl:"query"`
+	Body    string            `hcl:"body"`
+
+	// Variables sets additional flow variables, such as
+	// response.status.code, for conditions that depend on them.
+	Variables map[string]string `hcl:"variables"`
+}
+
+// DecodeRequest decodes a Request from HCL or JSON source.
+func DecodeRequest(filename string, src []byte) (*Request, error) {
+	list, err := dsl.ParseHCL(filename, src)
+	if err != nil {
+		return nil, err
+	}
+
+	var r Request
+	if err := hcl.DecodeObject(&r, list); err != nil {
+		return nil, err
+	}
+
+	if err := r.normalize(); err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+
+	return &r, nil
+}
+
+// normalize fills in defaults and moves a query string in Path into
+// Query.
+func (r *Request) normalize() error {
+	if r.Verb == "" {
+		r.Verb = "GET"
+	}
+	r.Verb = strings.ToUpper(r.Verb)
+
+	if r.Query == nil {
+		r.Query = make(map[string]string)
+	}
+
+	u, err := url.Parse(r.Path)
+	if err != nil {
+		return fmt.Errorf("invalid request path %q: %v", r.Path, err)
+	}
+
+	r.Path = u.Path
+	if r.Path == "" {
+		r.Path = "/"
+	}
+
+	for k, v := range u.Query() {
+		if _, ok := r.Query[k]; !ok && len(v) > 0 {
+			r.Query[k] = v[0]
+		}
+	}
+
+	return nil
+}
+
+func (r *Request) queryString() string {
+	values := make(url.Values)
+	for k, v := range r.Query {
+		values.Set(k, v)
+	}
+
+	return values.Encode()
+}
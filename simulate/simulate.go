@@ -0,0 +1,415 @@
+package simulate
+
+import (
+	"fmt"
+	"github.com/kevinswiber/apigee-hcl/dsl"
+	"github.com/kevinswiber/apigee-hcl/dsl/condition"
+	"github.com/kevinswiber/apigee-hcl/dsl/endpoints"
+	"github.com/kevinswiber/apigee-hcl/dsl/policies/policy"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Flow processing phases, in the order Apigee runs them.
+const (
+	ProxyRequest   = "proxy request"
+	RouteRules     = "route rules"
+	TargetRequest  = "target request"
+	TargetResponse = "target response"
+	ProxyResponse  = "proxy response"
+	PostClientFlow = "post client flow"
+)
+
+// EventKind identifies what an Event describes.
+type EventKind int
+
+// Event kinds
+const (
+	FlowEvent EventKind = iota
+	StepEvent
+	RouteRuleEvent
+	TargetEvent
+)
+
+// Event is a single entry in a Trace: a flow that was entered, a step
+// that was executed or skipped, a route rule that was evaluated, or the
+// request being sent to the target.
+type Event struct {
+	Kind  EventKind
+	Phase string
+
+	// Flow is the flow the event belongs to, e.g. PreFlow or
+	// Flow "get-cats".
+	Flow string
+
+	// Name is the step's policy name or the route rule's name.
+	Name string
+
+	// PolicyType is the XML element name of a step's policy, or empty
+	// if the policy isn't defined.
+	PolicyType string
+
+	// Condition is the condition that was evaluated, if any.
+	Condition string
+
+	// Result is true if the step executed, the conditional flow
+	// matched or the route rule was selected.
+	Result bool
+
+	// Disabled is true if a step was skipped because its policy has
+	// enabled = false.
+	Disabled bool
+
+	// Target describes where a selected route rule or the target
+	// endpoint sends the request.
+	Target string
+
+	// Err is set if the condition couldn't be evaluated.
+	Err error
+}
+
+// Trace is the statically computed path of a request through a proxy,
+// similar to an Apigee debug session.
+type Trace struct {
+	ProxyEndpoint  string
+	BasePath       string
+	PathSuffix     string
+	TargetEndpoint string
+	Events         []*Event
+}
+
+type simulator struct {
+	config    *dsl.Config
+	variables map[string]string
+	headers   map[string]string
+	trace     *Trace
+}
+
+// Run traces a request through the proxy described by c. The proxy
+// endpoint is chosen by the longest BasePath that prefixes the request
+// path; conditional flows and route rules are matched in order, and
+// only the first match runs.
+func Run(c *dsl.Config, r *Request) (*Trace, error) {
+	pe := matchProxyEndpoint(c.ProxyEndpoints, r.Path)
+	if pe == nil {
+		return nil, fmt.Errorf("no proxy endpoint base path matches %q", r.Path)
+	}
+
+	s := simulator{
+		config:    c,
+		variables: make(map[string]string),
+		headers:   make(map[string]string),
+		trace: &Trace{
+			ProxyEndpoint: pe.Name,
+			BasePath:      basePath(pe),
+		},
+	}
+	s.trace.PathSuffix = strings.TrimPrefix(r.Path, strings.TrimSuffix(s.trace.BasePath, "/"))
+	s.setRequestVariables(r, pe)
+
+	var preFlow, postFlow flowSteps
+	if pe.PreFlow != nil {
+		preFlow = flowSteps{pe.PreFlow.Request.Steps, pe.PreFlow.Response.Steps}
+	}
+	if pe.PostFlow != nil {
+		postFlow = flowSteps{pe.PostFlow.Request.Steps, pe.PostFlow.Response.Steps}
+	}
+
+	s.runFlow(ProxyRequest, "PreFlow", preFlow.request)
+	proxyFlow := s.matchFlow(ProxyRequest, pe.Flows)
+	if proxyFlow != nil {
+		s.runSteps(ProxyRequest, flowName(proxyFlow), proxyFlow.Request.Steps)
+	}
+	s.runFlow(ProxyRequest, "PostFlow", postFlow.request)
+
+	if te := s.matchRouteRule(pe.RouteRules); te != nil {
+		s.trace.TargetEndpoint = te.Name
+		s.variables["target.name"] = te.Name
+
+		var targetPreFlow, targetPostFlow flowSteps
+		if te.PreFlow != nil {
+			targetPreFlow = flowSteps{te.PreFlow.Request.Steps, te.PreFlow.Response.Steps}
+		}
+		if te.PostFlow != nil {
+			targetPostFlow = flowSteps{te.PostFlow.Request.Steps, te.PostFlow.Response.Steps}
+		}
+
+		s.runFlow(TargetRequest, "PreFlow", targetPreFlow.request)
+		targetFlow := s.matchFlow(TargetRequest, te.Flows)
+		if targetFlow != nil {
+			s.runSteps(TargetRequest, flowName(targetFlow), targetFlow.Request.Steps)
+		}
+		s.runFlow(TargetRequest, "PostFlow", targetPostFlow.request)
+		s.add(&Event{Kind: TargetEvent, Phase: TargetRequest, Target: targetURL(te)})
+
+		s.runFlow(TargetResponse, "PreFlow", targetPreFlow.response)
+		if targetFlow != nil {
+			s.runFlow(TargetResponse, flowName(targetFlow), targetFlow.Response.Steps)
+		}
+		s.runFlow(TargetResponse, "PostFlow", targetPostFlow.response)
+	}
+
+	s.runFlow(ProxyResponse, "PreFlow", preFlow.response)
+	if proxyFlow != nil {
+		s.runFlow(ProxyResponse, flowName(proxyFlow), proxyFlow.Response.Steps)
+	}
+	s.runFlow(ProxyResponse, "PostFlow", postFlow.response)
+
+	if pe.PostClientFlow != nil {
+		s.runFlow(PostClientFlow, "PostClientFlow", pe.PostClientFlow.Response.Steps)
+	}
+
+	return s.trace, nil
+}
+
+// flowSteps holds the request and response steps of a PreFlow or
+// PostFlow.
+type flowSteps struct {
+	request  []*endpoints.FlowStep
+	response []*endpoints.FlowStep
+}
+
+func matchProxyEndpoint(proxyEndpoints []*endpoints.ProxyEndpoint, p string) *endpoints.ProxyEndpoint {
+	var match *endpoints.ProxyEndpoint
+	for _, pe := range proxyEndpoints {
+		bp := strings.TrimSuffix(basePath(pe), "/")
+		if bp != "" && p != bp && !strings.HasPrefix(p, bp+"/") {
+			continue
+		}
+
+		if match == nil || len(bp) > len(strings.TrimSuffix(basePath(match), "/")) {
+			match = pe
+		}
+	}
+
+	return match
+}
+
+func basePath(pe *endpoints.ProxyEndpoint) string {
+	if pe.HTTPProxyConnection == nil || pe.HTTPProxyConnection.BasePath == "" {
+		return "/"
+	}
+	return pe.HTTPProxyConnection.BasePath
+}
+
+func targetURL(te *endpoints.TargetEndpoint) string {
+	switch {
+	case te.HTTPTargetConnection != nil && te.HTTPTargetConnection.URL != "":
+		return te.HTTPTargetConnection.URL
+	case te.HTTPTargetConnection != nil && te.HTTPTargetConnection.LoadBalancer != nil:
+		return "load balancer"
+	case te.LocalTargetConnection != nil:
+		return "local target connection"
+	case te.ScriptTarget != nil:
+		return "script target " + te.ScriptTarget.ResourceURL
+	}
+
+	return "target"
+}
+
+func flowName(f *endpoints.Flow) string {
+	return fmt.Sprintf("Flow %q", f.Name)
+}
+
+func (s *simulator) setRequestVariables(r *Request, pe *endpoints.ProxyEndpoint) {
+	query := r.queryString()
+	uri := r.Path
+	if query != "" {
+		uri += "?" + query
+	}
+
+	vars := map[string]string{
+		"verb":        r.Verb,
+		"path":        r.Path,
+		"uri":         uri,
+		"querystring": query,
+		"content":     r.Body,
+	}
+	for k, v := range vars {
+		s.variables["request."+k] = v
+		s.variables["message."+k] = v
+	}
+
+	for k, v := range r.Query {
+		s.variables["request.queryparam."+k] = v
+		s.variables["message.queryparam."+k] = v
+	}
+	s.variables["request.queryparams.count"] = strconv.Itoa(len(r.Query))
+
+	for k, v := range r.Headers {
+		s.headers[strings.ToLower(k)] = v
+	}
+	s.variables["request.headers.count"] = strconv.Itoa(len(r.Headers))
+
+	s.variables["proxy.name"] = pe.Name
+	s.variables["proxy.basepath"] = s.trace.BasePath
+	s.variables["proxy.pathsuffix"] = s.trace.PathSuffix
+	if s.config.Proxy != nil {
+		s.variables["apiproxy.name"] = s.config.Proxy.Name
+	}
+
+	for k, v := range r.Variables {
+		s.variables[k] = v
+	}
+}
+
+// lookup resolves a flow variable. Header names are case-insensitive.
+func (s *simulator) lookup(name string) (string, bool) {
+	if v, ok := s.variables[name]; ok {
+		return v, true
+	}
+
+	lower := strings.ToLower(name)
+	for _, prefix := range []string{"request.header.", "message.header."} {
+		if strings.HasPrefix(lower, prefix) {
+			v, ok := s.headers[strings.TrimPrefix(lower, prefix)]
+			return v, ok
+		}
+	}
+
+	return "", false
+}
+
+func (s *simulator) eval(cond string) (bool, error) {
+	return condition.EvalString(cond, s.lookup)
+}
+
+// runFlow records an unconditional flow and runs its steps. Flows
+// without steps are left out of the trace.
+func (s *simulator) runFlow(phase, flow string, steps []*endpoints.FlowStep) {
+	if len(steps) == 0 {
+		return
+	}
+
+	s.add(&Event{Kind: FlowEvent, Phase: phase, Flow: flow, Result: true})
+	s.runSteps(phase, flow, steps)
+}
+
+func (s *simulator) runSteps(phase, flow string, steps []*endpoints.FlowStep) {
+	for _, step := range steps {
+		p := s.policy(step.Name)
+		result, err := s.eval(step.Condition)
+		e := &Event{
+			Kind:      StepEvent,
+			Phase:     phase,
+			Flow:      flow,
+			Name:      step.Name,
+			Condition: step.Condition,
+			Result:    result,
+			Err:       err,
+		}
+
+		if p != nil {
+			e.PolicyType = policyElement(p)
+			if !policyEnabled(p) {
+				e.Result = false
+				e.Disabled = true
+			}
+		}
+
+		s.add(e)
+	}
+}
+
+// matchFlow returns the first conditional flow whose condition is true,
+// recording each flow that was evaluated.
+func (s *simulator) matchFlow(phase string, flows []*endpoints.Flow) *endpoints.Flow {
+	for _, f := range flows {
+		result, err := s.eval(f.Condition)
+		s.add(&Event{
+			Kind:      FlowEvent,
+			Phase:     phase,
+			Flow:      flowName(f),
+			Condition: f.Condition,
+			Result:    result,
+			Err:       err,
+		})
+
+		if !result {
+			continue
+		}
+
+		return f
+	}
+
+	return nil
+}
+
+// matchRouteRule returns the target endpoint of the first route rule
+// whose condition is true. It returns nil for a route rule without a
+// target endpoint, a route rule with a URL, or when no rule matches.
+func (s *simulator) matchRouteRule(rules []*endpoints.RouteRule) *endpoints.TargetEndpoint {
+	for _, rule := range rules {
+		result, err := s.eval(rule.Condition)
+		e := &Event{
+			Kind:      RouteRuleEvent,
+			Phase:     RouteRules,
+			Name:      rule.Name,
+			Condition: rule.Condition,
+			Result:    result,
+			Err:       err,
+		}
+		s.add(e)
+
+		if !result {
+			continue
+		}
+
+		switch {
+		case rule.TargetEndpoint != "":
+			e.Target = fmt.Sprintf("target endpoint %q", rule.TargetEndpoint)
+			for _, te := range s.config.TargetEndpoints {
+				if te.Name == rule.TargetEndpoint {
+					return te
+				}
+			}
+			e.Err = fmt.Errorf("target endpoint %q is not defined", rule.TargetEndpoint)
+		case rule.URL != "":
+			e.Target = fmt.Sprintf("URL %q", rule.URL)
+		default:
+			e.Target = "no target (null route)"
+		}
+
+		return nil
+	}
+
+	return nil
+}
+
+func (s *simulator) policy(name string) policy.Namer {
+	for _, p := range s.config.Policies {
+		if p.Name() == name {
+			return p
+		}
+	}
+
+	return nil
+}
+
+// policyElement returns the XML element name of a policy struct.
+func policyElement(p policy.Namer) string {
+	t := reflect.TypeOf(p)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if field, ok := t.FieldByName("XMLName"); ok {
+		return strings.Split(field.Tag.Get("xml"), ",")[0]
+	}
+
+	return ""
+}
+
+func policyEnabled(p policy.Namer) bool {
+	v := reflect.Indirect(reflect.ValueOf(p))
+	if enabled := v.FieldByName("Enabled"); enabled.IsValid() && enabled.Kind() == reflect.Bool {
+		return enabled.Bool()
+	}
+
+	return true
+}
+
+func (s *simulator) add(e *Event) {
+	s.trace.Events = append(s.trace.Events, e)
+}
@@ -0,0 +1,80 @@
+package simulate
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// Write writes a human-readable trace, grouped by phase.
+func (t *Trace) Write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, "proxy endpoint %q (base path %q, path suffix %q)\n",
+		t.ProxyEndpoint, t.BasePath, t.PathSuffix)
+
+	phase := ""
+	for _, e := range t.Events {
+		if e.Phase != phase {
+			phase = e.Phase
+			if phase == TargetRequest || phase == TargetResponse {
+				fmt.Fprintf(bw, "\n%s (target endpoint %q)\n", phase, t.TargetEndpoint)
+			} else {
+				fmt.Fprintf(bw, "\n%s\n", phase)
+			}
+		}
+
+		fmt.Fprintln(bw, e.String())
+	}
+
+	return bw.Flush()
+}
+
+// String formats an Event as an indented trace line.
+func (e *Event) String() string {
+	switch e.Kind {
+	case FlowEvent:
+		if e.Condition == "" {
+			return "  " + e.Flow
+		}
+		status := "not matched"
+		if e.Result {
+			status = "matched"
+		}
+		return fmt.Sprintf("  %s: %s%s", e.Flow, status, e.detail())
+	case StepEvent:
+		policyType := e.PolicyType
+		if policyType == "" {
+			policyType = "undefined policy"
+		}
+
+		status := "skipped"
+		if e.Result {
+			status = "executed"
+		} else if e.Disabled {
+			status = "skipped (disabled)"
+		}
+		return fmt.Sprintf("    %s (%s): %s%s", e.Name, policyType, status, e.detail())
+	case RouteRuleEvent:
+		if !e.Result {
+			return fmt.Sprintf("  RouteRule %q: not matched%s", e.Name, e.detail())
+		}
+		return fmt.Sprintf("  RouteRule %q: selected, %s%s", e.Name, e.Target, e.detail())
+	case TargetEvent:
+		return fmt.Sprintf("  request sent to %s", e.Target)
+	}
+
+	return ""
+}
+
+func (e *Event) detail() string {
+	if e.Err != nil {
+		return fmt.Sprintf(", error: %v", e.Err)
+	}
+
+	if e.Condition == "" || e.Disabled {
+		return ""
+	}
+
+	return fmt.Sprintf(", condition %v: %s", e.Result, e.Condition)
+}
@@ -0,0 +1,115 @@
+proxy "AccessControlFixture" {}
+
+proxy_endpoint "default" {
+  http_proxy_connection {
+    base_path    = "/v0/partners"
+    virtual_host = ["secure"]
+  }
+
+  pre_flow {
+    request {
+      step "partner-ip-allowlist" {}
+      step "decode-basic-auth" {}
+      step "get-developer" {}
+    }
+  }
+
+  route_rule "default" {
+    target_endpoint = "default"
+  }
+}
+
+target_endpoint "default" {
+  http_target_connection {
+    url = "http://mocktarget.apigee.net"
+  }
+
+  pre_flow {
+    request {
+      step "encode-basic-auth" {}
+    }
+  }
+}
+
+policy access_control "partner-ip-allowlist" {
+  display_name      = "Partner IP Allowlist"
+  validate_based_on = "X_FORWARDED_FOR_ALL_IP"
+
+  ip_rules {
+    no_rule_match_action = "DENY"
+
+    match_rule {
+      action = "ALLOW"
+
+      source_address {
+        mask  = 24
+        value = "10.10.20.0"
+      }
+
+      source_address {
+        mask  = 32
+        value = "{request.header.partner_ip}"
+      }
+    }
+
+    match_rule {
+      action = "DENY"
+
+      source_address {
+        mask  = 32
+        value = "10.10.20.13"
+      }
+    }
+  }
+}
+
+policy basic_authentication "decode-basic-auth" {
+  display_name = "Decode Basic Auth"
+  operation    = "Decode"
+  source       = "request.header.Authorization"
+
+  user {
+    ref = "partner.username"
+  }
+
+  password {
+    ref = "partner.password"
+  }
+}
+
+policy basic_authentication "encode-basic-auth" {
+  operation                   = "Encode"
+  ignore_unresolved_variables = false
+
+  user {
+    ref = "private.backend.username"
+  }
+
+  password {
+    ref = "private.backend.password"
+  }
+
+  assign_to {
+    create_new = false
+    value      = "request.header.Authorization"
+  }
+}
+
+policy access_entity "get-developer" {
+  display_name  = "Get Developer"
+  output_format = "JSON"
+
+  entity_type {
+    value = "developer"
+  }
+
+  entity_identifier {
+    ref  = "partner.username"
+    type = "developeremail"
+  }
+
+  secondary_identifier {
+    ref  = "request.header.app_name"
+    type = "appname"
+  }
+}
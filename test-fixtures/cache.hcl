@@ -0,0 +1,97 @@
+proxy "CacheFixture" {}
+
+proxy_endpoint "default" {
+  http_proxy_connection {
+    base_path    = "/v0/cache"
+    virtual_host = ["default", "secure"]
+  }
+
+  pre_flow {
+    request {
+      step "lookup-token" {}
+    }
+  }
+
+  flow "purge" {
+    condition = "request.verb = \"DELETE\""
+
+    request {
+      step "invalidate-token" {}
+    }
+  }
+
+  route_rule "default" {
+    target_endpoint = "default"
+  }
+}
+
+target_endpoint "default" {
+  http_target_connection {
+    url = "http://mocktarget.apigee.net"
+  }
+
+  post_flow {
+    response {
+      step "populate-token" {
+        condition = "cached.token = null"
+      }
+    }
+  }
+}
+
+policy lookup_cache "lookup-token" {
+  display_name   = "Lookup Token"
+  cache_resource = "tokens"
+  scope          = "Exclusive"
+  lookup_timeout = 30
+  assign_to      = "cached.token"
+
+  cache_key {
+    prefix = "token"
+
+    key_fragment {
+      ref = "request.header.client_id"
+    }
+  }
+}
+
+policy populate_cache "populate-token" {
+  display_name   = "Populate Token"
+  cache_resource = "tokens"
+  scope          = "Exclusive"
+  source         = "response.content"
+
+  cache_key {
+    prefix = "token"
+
+    key_fragment {
+      ref = "request.header.client_id"
+    }
+  }
+
+  expiry_settings {
+    timeout_in_sec {
+      ref   = "response.header.expires_in"
+      value = 3600
+    }
+  }
+}
+
+policy invalidate_cache "invalidate-token" {
+  cache_resource      = "tokens"
+  scope               = "Exclusive"
+  purge_child_entries = true
+
+  cache_key {
+    prefix = "token"
+
+    key_fragment {
+      ref = "request.header.client_id"
+    }
+  }
+
+  cache_context {
+    api_proxy_name = "CacheFixture"
+    proxy_name     = "default"
+  }
+}
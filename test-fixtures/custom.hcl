@@ -0,0 +1,63 @@
+proxy "CustomFixture" {}
+
+proxy_endpoint "default" {
+  http_proxy_connection {
+    base_path    = "/v0/custom"
+    virtual_host = ["default", "secure"]
+  }
+
+  pre_flow {
+    request {
+      step "generate-jwt" {}
+    }
+  }
+
+  route_rule "default" {
+    target_endpoint = "default"
+  }
+}
+
+target_endpoint "default" {
+  http_target_connection {
+    url = "http://mocktarget.apigee.net"
+  }
+}
+
+policy custom "generate-jwt" {
+  element           = "GenerateJWT"
+  continue_on_error = true
+
+  body {
+    DisplayName = "Generate JWT"
+    Algorithm   = "HS256"
+
+    SecretKey {
+      Value {
+        "@ref" = "private.secretkey"
+      }
+
+      Id = "1918290"
+    }
+
+    ExpiresIn = "1h"
+    Subject   = "monty-pythons-flying-circus"
+    Issuer    = "urn://apigee-edge-JWT-policy-test"
+    Audience  = ["audience1", "audience2"]
+
+    AdditionalClaims {
+      Claim {
+        "@name"  = "show"
+        "@type"  = "string"
+        "#text"  = "And now for something completely different."
+      }
+
+      Claim {
+        "@name" = "episode"
+        "@type" = "number"
+        "#text" = 27
+      }
+    }
+
+    OutputVariable = "jwt-variable"
+  }
+}
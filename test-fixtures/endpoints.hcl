@@ -172,3 +172,11 @@ policy quota "check-quota" {
     sync_message_count       = 5
   }
 }
+
+policy assign_message "GenerateAccessToken" {}
+
+policy assign_message "FakePolicy" {}
+
+policy assign_message "FakeFaultRuleStep" {}
+
+policy assign_message "FakeFaultRuleStep2" {}
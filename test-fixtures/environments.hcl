@@ -0,0 +1,72 @@
+variable "quota_count" {
+  default = 10
+}
+
+proxy "Environments" {}
+
+proxy_endpoint "default" {
+  pre_flow {
+    request {
+      step "check-quota" {}
+    }
+  }
+
+  http_proxy_connection {
+    base_path    = "/v0/hello"
+    virtual_host = ["default"]
+  }
+
+  route_rule "default" {
+    target_endpoint = "default"
+  }
+}
+
+target_endpoint "default" {
+  http_target_connection {
+    url = "http://dev.example.com"
+  }
+}
+
+policy quota "check-quota" {
+  allow {
+    count = "${var.quota_count}"
+  }
+
+  interval {
+    value = 1
+  }
+
+  time_unit {
+    value = "minute"
+  }
+}
+
+environment "test" {
+  target_endpoint "default" {
+    http_target_connection {
+      url = "https://test.example.com"
+    }
+  }
+}
+
+environment "prod" {
+  variable "quota_count" {
+    default = 1000
+  }
+
+  proxy_endpoint "default" {
+    http_proxy_connection {
+      virtual_host = ["secure"]
+    }
+  }
+
+  target_endpoint "default" {
+    http_target_connection {
+      url = "https://api.example.com"
+    }
+
+    ssl_info {
+      enabled = true
+    }
+  }
+}
@@ -0,0 +1,37 @@
+proxy "FlowCalloutFixture" {}
+
+proxy_endpoint "default" {
+  http_proxy_connection {
+    base_path    = "/v0/secure"
+    virtual_host = ["secure"]
+  }
+
+  pre_flow {
+    request {
+      step "common-security" {}
+    }
+  }
+
+  route_rule "default" {
+    target_endpoint = "default"
+  }
+}
+
+target_endpoint "default" {
+  http_target_connection {
+    url = "http://mocktarget.apigee.net"
+  }
+}
+
+policy flow_callout "common-security" {
+  display_name       = "Common Security"
+  shared_flow_bundle = "common-security"
+
+  parameter "client" {
+    ref = "request.header.client_id"
+  }
+
+  parameter "mode" {
+    value = "strict"
+  }
+}
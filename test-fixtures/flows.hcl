@@ -0,0 +1,166 @@
+proxy "Flows" {}
+
+proxy_endpoint "default" {
+  pre_flow {
+    request {
+      step "verify-key" {}
+    }
+
+    response {
+      step "add-cors" {}
+    }
+  }
+
+  flow "get-cats" {
+    description = "List cats"
+    condition   = "(proxy.pathsuffix MatchesPath \"/cats\") and (request.verb = \"GET\")"
+
+    request {
+      step "check-quota" {}
+    }
+
+    response {
+      step "add-cors" {
+        condition = "request.header.origin != null"
+      }
+    }
+  }
+
+  flow "request-only" {
+    condition = "request.verb = \"POST\""
+
+    request {
+      step "check-quota" {}
+    }
+  }
+
+  flow "response-only" {
+    description = "Only runs on the response"
+
+    response {
+      step "add-cors" {}
+    }
+  }
+
+  post_flow {
+    request {
+      step "check-quota" {}
+    }
+  }
+
+  post_client_flow {
+    response {
+      step "log-request" {}
+    }
+  }
+
+  fault_rule "invalid-key" {
+    condition = "fault.name = \"InvalidApiKey\""
+    step "raise-unauthorized" {}
+  }
+
+  default_fault_rule "default" {
+    always_enforce = true
+    step "raise-unauthorized" {}
+  }
+
+  http_proxy_connection {
+    base_path    = "/v0/flows"
+    virtual_host = ["default"]
+  }
+
+  route_rule "default" {
+    target_endpoint = "default"
+  }
+}
+
+target_endpoint "default" {
+  pre_flow {
+    request {
+      step "add-cors" {}
+    }
+
+    response {
+      step "add-cors" {}
+    }
+  }
+
+  flow "stream" {
+    description = "Server-sent events"
+    condition   = "proxy.pathsuffix MatchesPath \"/stream\""
+
+    request {
+      step "check-quota" {}
+    }
+  }
+
+  post_flow {
+    response {
+      step "add-cors" {}
+    }
+  }
+
+  event_flow "EventFlow" {
+    content_type = "text/event-stream"
+
+    response {
+      step "log-request" {}
+    }
+  }
+
+  fault_rule "target-error" {
+    condition = "fault.name = \"ServiceUnavailable\""
+    step "raise-unauthorized" {}
+  }
+
+  default_fault_rule "target-default" {
+    step "raise-unauthorized" {}
+  }
+
+  http_target_connection {
+    url = "http://mocktarget.apigee.net"
+  }
+}
+
+policy verify_api_key "verify-key" {
+  apikey {
+    ref = "request.queryparam.apikey"
+  }
+}
+
+policy assign_message "add-cors" {
+  add {
+    header "Access-Control-Allow-Origin" {
+      value = "*"
+    }
+  }
+}
+
+policy quota "check-quota" {
+  allow {
+    count = 10
+  }
+
+  interval {
+    value = 1
+  }
+
+  time_unit {
+    value = "minute"
+  }
+}
+
+policy assign_message "log-request" {
+  assign_variable {
+    name  = "logged"
+    value = "true"
+  }
+}
+
+policy raise_fault "raise-unauthorized" {
+  fault_response {
+    set {
+      status_code = 401
+    }
+  }
+}
@@ -0,0 +1,17 @@
+proxy "invalid-condition" {}
+
+proxy_endpoint "default" {
+  http_proxy_connection {
+    base_path = "/v0/conditions"
+  }
+
+  flow "unbalanced" {
+    condition = "(proxy.pathsuffix MatchesPath \"/cats\""
+  }
+
+  flow "missing-operand" {
+    condition = "request.verb = "
+  }
+
+  route_rule "default" {}
+}
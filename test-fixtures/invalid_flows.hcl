@@ -0,0 +1,40 @@
+proxy "InvalidFlows" {}
+
+proxy_endpoint "default" {
+  pre_flow {}
+  pre_flow {}
+
+  event_flow {
+    response {}
+  }
+
+  post_client_flow {
+    request {
+      step "add-cors" {}
+    }
+  }
+
+  http_proxy_connection {
+    base_path = "/v0/invalid"
+  }
+
+  route_rule "default" {
+    target_endpoint = "default"
+  }
+}
+
+target_endpoint "default" {
+  post_client_flow {
+    response {}
+  }
+
+  event_flow {
+    request {}
+  }
+
+  http_target_connection {
+    url = "http://mocktarget.apigee.net"
+  }
+}
+
+policy assign_message "add-cors" {}
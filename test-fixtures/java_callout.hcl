@@ -0,0 +1,35 @@
+proxy "JavaCalloutFixture" {}
+
+proxy_endpoint "default" {
+  http_proxy_connection {
+    base_path    = "/v0/java"
+    virtual_host = ["default", "secure"]
+  }
+
+  pre_flow {
+    request {
+      step "add-headers" {}
+    }
+  }
+
+  route_rule "default" {
+    target_endpoint = "default"
+  }
+}
+
+target_endpoint "default" {
+  http_target_connection {
+    url = "http://mocktarget.apigee.net"
+  }
+}
+
+policy java_callout "add-headers" {
+  display_name = "Add Headers"
+  class_name   = "com.example.callout.AddHeaders"
+  jar          = "java/header-callout.jar"
+
+  properties {
+    prefix = "X-Example-"
+    count  = 2
+  }
+}
@@ -0,0 +1,125 @@
+proxy "KeyValueMapFixture" {}
+
+proxy_endpoint "default" {
+  http_proxy_connection {
+    base_path    = "/v0/kvm"
+    virtual_host = ["default", "secure"]
+  }
+
+  pre_flow {
+    request {
+      step "get-client-config" {}
+    }
+  }
+
+  flow "update" {
+    condition = "request.verb = \"PUT\""
+
+    request {
+      step "put-client-config" {}
+    }
+  }
+
+  flow "remove" {
+    condition = "request.verb = \"DELETE\""
+
+    request {
+      step "delete-client-config" {}
+    }
+  }
+
+  route_rule "default" {
+    target_endpoint = "default"
+  }
+}
+
+target_endpoint "default" {
+  http_target_connection {
+    url = "http://mocktarget.apigee.net"
+  }
+}
+
+policy key_value_map_operations "get-client-config" {
+  display_name        = "Get Client Config"
+  map_identifier      = "client-config"
+  scope               = "environment"
+  expiry_time_in_secs = 300
+
+  initial_entry {
+    key {
+      parameter {
+        value = "feature.beta"
+      }
+    }
+
+    value = ["false"]
+  }
+
+  initial_entry {
+    key {
+      parameter {
+        value = "regions"
+      }
+    }
+
+    value = ["us-east", "eu-west"]
+  }
+
+  get {
+    assign_to = "flags.beta"
+
+    key {
+      parameter {
+        value = "feature.beta"
+      }
+    }
+  }
+
+  get {
+    assign_to = "client.region"
+    index     = 2
+
+    key {
+      parameter {
+        ref = "request.header.client_id"
+      }
+
+      parameter {
+        value = "region"
+      }
+    }
+  }
+}
+
+policy key_value_map_operations "put-client-config" {
+  map_identifier = "client-config"
+  scope          = "environment"
+
+  put {
+    override = true
+
+    key {
+      parameter {
+        ref = "request.header.client_id"
+      }
+    }
+
+    value {
+      ref = "request.content"
+    }
+  }
+}
+
+policy key_value_map_operations "delete-client-config" {
+  map_identifier = "client-config"
+  scope          = "environment"
+
+  delete {
+    key {
+      parameter {
+        ref   = "request.header.client_id"
+        value = "default-client"
+      }
+    }
+  }
+}
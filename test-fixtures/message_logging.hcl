@@ -0,0 +1,64 @@
+proxy "MessageLoggingFixture" {}
+
+proxy_endpoint "default" {
+  http_proxy_connection {
+    base_path    = "/v0/logged"
+    virtual_host = ["default", "secure"]
+  }
+
+  route_rule "default" {
+    target_endpoint = "default"
+  }
+
+  post_client_flow {
+    response {
+      step "log-to-syslog" {}
+      step "log-to-file" {}
+    }
+  }
+}
+
+target_endpoint "default" {
+  http_target_connection {
+    url = "http://mocktarget.apigee.net"
+  }
+}
+
+policy message_logging "log-to-syslog" {
+  display_name   = "Log to Syslog"
+  log_level      = "ALERT"
+  buffer_message = true
+
+  syslog {
+    message        = "[audit] {request.verb} {request.uri} -> {response.status.code}"
+    host           = "logs.example.com"
+    port           = 6514
+    protocol       = "TCP"
+    format_message = true
+
+    ssl_info {
+      enabled = true
+    }
+  }
+}
+
+policy message_logging "log-to-file" {
+  log_level = "INFO"
+
+  file {
+    message   = "{messageid} {request.verb} {request.uri}"
+    file_name = "audit.log"
+
+    file_rotation_options {
+      rotate_file_on_startup = true
+      file_rotation_type     = "SIZE"
+      max_file_size_in_mb    = 10
+      max_files_to_retain    = 5
+
+      rotation_frequency {
+        unit  = "minute"
+        value = 10
+      }
+    }
+  }
+}
@@ -0,0 +1,82 @@
+proxy "MessageValidationFixture" {}
+
+proxy_endpoint "default" {
+  http_proxy_connection {
+    base_path    = "/v0/orders"
+    virtual_host = ["default", "secure"]
+  }
+
+  pre_flow {
+    request {
+      step "check-limits" {}
+      step "validate-envelope" {}
+      step "validate-order" {}
+    }
+  }
+
+  route_rule "default" {
+    target_endpoint = "default"
+  }
+}
+
+target_endpoint "default" {
+  http_target_connection {
+    url = "http://mocktarget.apigee.net"
+  }
+}
+
+policy monetization_limits_check "check-limits" {
+  display_name                = "Check Limits"
+  ignore_unresolved_variables = true
+
+  variables {
+    product = "apiproduct.name"
+  }
+
+  fault_response {
+    set {
+      payload = {
+        content_type = "text/xml"
+        value        = "<error><message>Usage has been exceeded ({mint.limitsViolated})</message></error>"
+      }
+
+      status_code   = 403
+      reason_phrase = "Forbidden"
+    }
+  }
+}
+
+policy message_validation "validate-envelope" {
+  display_name = "Validate Envelope"
+  source       = "request"
+  resource_url = "wsdl://orders.wsdl"
+
+  soap_message {
+    version = "1.1/1.2"
+  }
+
+  element "order" {
+    namespace = "http://example.com/orders"
+  }
+
+  content = <<EOF
+<?xml version="1.0" encoding="UTF-8"?>
+<definitions xmlns="http://schemas.xmlsoap.org/wsdl/" name="Orders"
+    targetNamespace="http://example.com/orders">
+  <types>
+    <xsd:schema xmlns:xsd="http://www.w3.org/2001/XMLSchema">
+      <xsd:import namespace="http://example.com/orders" schemaLocation="xsd://order.xsd"/>
+    </xsd:schema>
+  </types>
+</definitions>
+EOF
+}
+
+policy message_validation "validate-order" {
+  source = "request"
+  file   = "xsd/order.xsd"
+
+  element "order" {
+    namespace = "http://example.com/orders"
+  }
+}
@@ -0,0 +1,41 @@
+variable "origin" {
+  default = "https://example.com"
+}
+
+proxy "ModuleFixture" {}
+
+proxy_endpoint "default" {
+  http_proxy_connection {
+    base_path    = "/v0/module"
+    virtual_host = ["default", "secure"]
+  }
+
+  pre_flow {
+    request {
+      step "cors.preflight" {
+        condition = "request.verb == \"OPTIONS\""
+      }
+    }
+  }
+
+  post_flow {
+    response {
+      step "cors.add-cors" {}
+    }
+  }
+
+  route_rule "default" {
+    target_endpoint = "default"
+  }
+}
+
+target_endpoint "default" {
+  http_target_connection {
+    url = "http://mocktarget.apigee.net"
+  }
+}
+
+module "cors" {
+  source         = "modules/cors"
+  allowed_origin = "${var.origin}"
+}
@@ -0,0 +1,34 @@
+policy assign_message "add-cors" {
+  display_name                = "Add CORS"
+  ignore_unresolved_variables = true
+
+  add {
+    header "Access-Control-Allow-Origin" {
+      value = "${var.allowed_origin}"
+    }
+
+    header "Access-Control-Allow-Headers" {
+      value = "origin, x-requested-with, accept"
+    }
+
+    header "Access-Control-Allow-Methods" {
+      value = "${var.allowed_methods}"
+    }
+  }
+
+  assign_to {
+    create_new = false
+    type       = "response"
+  }
+}
+
+policy raise_fault "preflight" {
+  display_name = "Preflight"
+
+  fault_response {
+    set {
+      status_code   = 200
+      reason_phrase = "OK"
+    }
+  }
+}
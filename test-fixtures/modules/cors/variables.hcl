@@ -0,0 +1,7 @@
+variable "allowed_origin" {
+  default = "*"
+}
+
+variable "allowed_methods" {
+  default = "GET, PUT, POST, DELETE"
+}
@@ -0,0 +1,181 @@
+proxy "OAuthV2Fixture" {}
+
+proxy_endpoint "default" {
+  http_proxy_connection {
+    base_path    = "/v0/oauth"
+    virtual_host = ["secure"]
+  }
+
+  flow "token" {
+    condition = "proxy.pathsuffix MatchesPath \"/token\" and request.verb = \"POST\""
+
+    request {
+      step "generate-access-token" {
+        condition = "request.formparam.grant_type != \"refresh_token\""
+      }
+
+      step "refresh-access-token" {
+        condition = "request.formparam.grant_type = \"refresh_token\""
+      }
+    }
+  }
+
+  flow "authorize" {
+    condition = "proxy.pathsuffix MatchesPath \"/authorize\" and request.verb = \"GET\""
+
+    request {
+      step "generate-authorization-code" {}
+    }
+  }
+
+  flow "revoke" {
+    condition = "proxy.pathsuffix MatchesPath \"/revoke\" and request.verb = \"POST\""
+
+    request {
+      step "invalidate-token" {}
+      step "delete-token-info" {}
+    }
+  }
+
+  flow "userinfo" {
+    condition = "proxy.pathsuffix MatchesPath \"/userinfo\""
+
+    request {
+      step "verify-access-token" {}
+      step "get-token-info" {}
+      step "set-token-info" {}
+    }
+  }
+
+  route_rule "noroute" {}
+}
+
+policy oauth_v2 "generate-access-token" {
+  display_name = "Generate Access Token"
+  operation    = "GenerateAccessToken"
+
+  expires_in {
+    ref   = "kvm.oauth.expires_in"
+    value = 3600000
+  }
+
+  refresh_token_expires_in {
+    value = 86400000
+  }
+
+  supported_grant_types = ["authorization_code", "client_credentials", "password"]
+
+  grant_type                     = "request.formparam.grant_type"
+  code                           = "request.formparam.code"
+  client_id                      = "request.formparam.client_id"
+  redirect_uri                   = "request.formparam.redirect_uri"
+  scope                          = "request.formparam.scope"
+  app_end_user                   = "request.header.app_end_user"
+  user_name                      = "request.formparam.username"
+  password                       = "request.formparam.password"
+  rfc_compliant_request_response = true
+
+  attribute "department" {
+    ref     = "request.header.department"
+    display = true
+    value   = "engineering"
+  }
+
+  attribute "tier" {
+    value = "gold"
+  }
+
+  generate_response {
+    enabled = true
+  }
+}
+
+policy oauth_v2 "refresh-access-token" {
+  operation     = "RefreshAccessToken"
+  grant_type    = "request.formparam.grant_type"
+  refresh_token = "request.formparam.refresh_token"
+
+  expires_in {
+    value = 1800000
+  }
+
+  reuse_refresh_token = true
+
+  generate_response {
+    enabled = true
+  }
+}
+
+policy oauth_v2 "generate-authorization-code" {
+  operation     = "GenerateAuthorizationCode"
+  client_id     = "request.queryparam.client_id"
+  redirect_uri  = "request.queryparam.redirect_uri"
+  response_type = "request.queryparam.response_type"
+  scope         = "request.queryparam.scope"
+  state         = "request.queryparam.state"
+
+  expires_in {
+    value = 600000
+  }
+
+  generate_response {
+    enabled = true
+  }
+}
+
+policy oauth_v2 "verify-access-token" {
+  operation           = "VerifyAccessToken"
+  access_token        = "request.header.authorization"
+  access_token_prefix = "Bearer"
+
+  cache_expiry_in_seconds {
+    ref   = "request.queryparam.cache_expiry"
+    value = 300
+  }
+
+  generate_error_response {
+    enabled = true
+  }
+}
+
+policy oauth_v2 "invalidate-token" {
+  operation = "InvalidateToken"
+
+  token {
+    type    = "accesstoken"
+    cascade = true
+    value   = "request.formparam.token"
+  }
+
+  token {
+    type  = "refreshtoken"
+    value = "request.formparam.refresh_token"
+  }
+}
+
+policy get_oauth_v2_info "get-token-info" {
+  display_name = "Get Token Info"
+
+  access_token {
+    ref = "request.header.access_token"
+  }
+
+  ignore_access_token_status = true
+}
+
+policy set_oauth_v2_info "set-token-info" {
+  access_token {
+    ref = "request.header.access_token"
+  }
+
+  attribute "last_seen" {
+    ref   = "system.timestamp"
+    value = "0"
+  }
+}
+
+policy delete_oauth_v2_info "delete-token-info" {
+  authorization_code {
+    ref = "request.formparam.code"
+  }
+}
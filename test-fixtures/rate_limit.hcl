@@ -0,0 +1,95 @@
+proxy "RateLimitFixture" {}
+
+proxy_endpoint "default" {
+  http_proxy_connection {
+    base_path    = "/v0/limited"
+    virtual_host = ["default", "secure"]
+  }
+
+  pre_flow {
+    request {
+      step "check-quota" {}
+
+      step "reset-quota" {
+        condition = "request.header.x-reset = \"true\""
+      }
+    }
+  }
+
+  route_rule "default" {
+    target_endpoint = "default"
+  }
+}
+
+target_endpoint "default" {
+  http_target_connection {
+    url = "http://mocktarget.apigee.net"
+  }
+
+  pre_flow {
+    request {
+      step "limit-connections" {}
+    }
+  }
+
+  post_flow {
+    response {
+      step "limit-connections" {}
+    }
+  }
+}
+
+policy quota "check-quota" {
+  type = "calendar"
+
+  allow {
+    count = 100
+  }
+
+  interval {
+    value = 1
+  }
+
+  time_unit {
+    value = "hour"
+  }
+
+  start_time = "2016-3-31 00:00:00"
+
+  identifier {
+    ref = "request.header.client_id"
+  }
+}
+
+policy reset_quota "reset-quota" {
+  display_name = "Reset Quota"
+
+  quota "check-quota" {
+    identifier "_default" {
+      allow {
+        value = 100
+      }
+    }
+
+    identifier {
+      ref = "request.header.client_id"
+
+      allow {
+        ref = "request.header.allowquota"
+      }
+    }
+  }
+}
+
+policy concurrent_rate_limit "limit-connections" {
+  display_name  = "Limit Connections"
+  distributed   = true
+  strict_on_ttl = false
+
+  allow_connections {
+    count = 200
+    ttl   = 5
+  }
+
+  target_identifier "default" {}
+}
@@ -0,0 +1,141 @@
+proxy "SAMLFixture" {}
+
+proxy_endpoint "default" {
+  http_proxy_connection {
+    base_path    = "/v0/saml"
+    virtual_host = ["secure"]
+  }
+
+  pre_flow {
+    request {
+      step "validate-saml" {}
+      step "verify-request-token" {}
+
+      step "generate-request-token" {
+        condition = "proxy.pathsuffix MatchesPath \"/request_token\""
+      }
+    }
+  }
+
+  route_rule "default" {
+    target_endpoint = "default"
+  }
+}
+
+target_endpoint "default" {
+  http_target_connection {
+    url = "https://mocktarget.apigee.net"
+  }
+
+  pre_flow {
+    request {
+      step "generate-saml" {}
+    }
+  }
+}
+
+policy generate_saml_assertion "generate-saml" {
+  display_name               = "Generate SAML"
+  ignore_content_type        = false
+  canonicalization_algorithm = "http://www.w3.org/2001/10/xml-exc-c14n#"
+  signature_algorithm        = "http://www.w3.org/2000/09/xmldsig#rsa-sha1"
+
+  issuer {
+    ref   = "saml.issuer"
+    value = "apigee"
+  }
+
+  subject {
+    ref = "apiproxy.name"
+  }
+
+  key_store {
+    name {
+      value = "saml-keystore"
+    }
+
+    alias {
+      ref = "saml.alias"
+    }
+  }
+
+  output_variable {
+    flow_variable = "assertion.content"
+
+    message "request" {
+      namespace "soap" {
+        value = "http://schemas.xmlsoap.org/soap/envelope/"
+      }
+
+      xpath = "/soap:Envelope/soap:Header"
+    }
+  }
+
+  template {
+    ignore_unresolved_variables = true
+
+    content = <<EOF
+<saml:Assertion xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion" Version="2.0">
+  <saml:Issuer>{saml.issuer}</saml:Issuer>
+  <saml:Subject>
+    <saml:NameID>{saml.subject}</saml:NameID>
+  </saml:Subject>
+</saml:Assertion>
+EOF
+  }
+}
+
+policy validate_saml_assertion "validate-saml" {
+  display_name     = "Validate SAML"
+  trust_store      = "saml-truststore"
+  remove_assertion = true
+
+  source "request" {
+    namespace "soap" {
+      value = "http://schemas.xmlsoap.org/soap/envelope/"
+    }
+
+    namespace "saml" {
+      value = "urn:oasis:names:tc:SAML:2.0:assertion"
+    }
+
+    xpath = "/soap:Envelope/soap:Header/saml:Assertion"
+  }
+}
+
+policy oauth_v1 "verify-request-token" {
+  display_name = "Verify Access Token"
+  operation    = "VerifyAccessToken"
+
+  access_token {
+    ref = "request.header.oauth_token"
+  }
+}
+
+policy oauth_v1 "generate-request-token" {
+  operation = "GenerateRequestToken"
+
+  url {
+    ref = "request.url"
+  }
+
+  expires_in {
+    value = 3600000
+  }
+
+  attribute "partner" {
+    ref     = "request.header.partner"
+    display = true
+  }
+
+  generate_response {
+    enabled = true
+    format  = "FORM_PARAM"
+  }
+
+  generate_error_response {
+    enabled = true
+    format  = "XML"
+    realm   = "http://oauth.example.com/oauth/1/"
+  }
+}
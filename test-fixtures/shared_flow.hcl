@@ -0,0 +1,28 @@
+shared_flow "common-security" {
+  display_name = "Common Security"
+  description  = "API key verification and request logging shared by all proxies"
+
+  step "verify-api-key" {}
+
+  step "log-request" {
+    condition = "request.header.x-debug = \"true\""
+  }
+}
+
+policy verify_api_key "verify-api-key" {
+  display_name = "Verify API Key"
+
+  apikey {
+    ref = "request.header.apikey"
+  }
+}
+
+policy message_logging "log-request" {
+  display_name = "Log Request"
+
+  syslog {
+    message = "[{organization.name}] {request.verb} {request.uri}"
+    host    = "logs.example.com"
+    port    = 514
+  }
+}
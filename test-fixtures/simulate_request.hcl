@@ -0,0 +1,8 @@
+verb = "POST"
+path = "/v0/hello/accesstoken?grant_type=client_credentials"
+
+headers {
+  Content-Type = "application/x-www-form-urlencoded"
+}
+
+body = "client_id=abc&client_secret=xyz"
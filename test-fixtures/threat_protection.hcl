@@ -0,0 +1,139 @@
+proxy "ThreatProtectionFixture" {}
+
+proxy_endpoint "default" {
+  http_proxy_connection {
+    base_path    = "/v0/protected"
+    virtual_host = ["default", "secure"]
+  }
+
+  pre_flow {
+    request {
+      step "regex-protection" {}
+
+      step "json-threat-protection" {
+        condition = "request.header.Content-Type = \"application/json\""
+      }
+
+      step "xml-threat-protection" {
+        condition = "request.header.Content-Type = \"text/xml\""
+      }
+    }
+  }
+
+  route_rule "default" {
+    target_endpoint = "default"
+  }
+}
+
+target_endpoint "default" {
+  http_target_connection {
+    url = "http://mocktarget.apigee.net"
+  }
+}
+
+policy json_threat_protection "json-threat-protection" {
+  display_name             = "JSON Threat Protection"
+  source                   = "request"
+  array_element_count      = 20
+  container_depth          = 10
+  object_entry_count       = 15
+  object_entry_name_length = 50
+  string_value_length      = 500
+}
+
+policy xml_threat_protection "xml-threat-protection" {
+  display_name = "XML Threat Protection"
+  source       = "request"
+
+  name_limits {
+    element                       = 10
+    attribute                     = 10
+    namespace_prefix              = 10
+    processing_instruction_target = 10
+  }
+
+  structure_limits {
+    node_depth                  = 5
+    attribute_count_per_element = 2
+    namespace_count_per_element = 3
+
+    child_count {
+      include_comment = false
+      include_text    = true
+      value           = 3
+    }
+  }
+
+  value_limits {
+    text                        = 15
+    attribute                   = 10
+    namespace_uri               = 10
+    comment                     = 10
+    processing_instruction_data = 10
+  }
+}
+
+policy regular_expression_protection "regex-protection" {
+  display_name                = "Regular Expression Protection"
+  source                      = "request"
+  ignore_unresolved_variables = true
+
+  uri_path {
+    pattern {
+      value = "[\\s]*(?i)((delete)|(exec)|(drop\\s*table))"
+    }
+  }
+
+  query_param "q" {
+    pattern {
+      value = "<\\s*script\\b[^>]*>[^<]+<\\s*/\\s*script\\s*>"
+    }
+  }
+
+  header "X-Forwarded-For" {
+    pattern {
+      value = "[^0-9.,\\s]"
+    }
+  }
+
+  form_param "comment" {
+    pattern {
+      value = "<\\s*script\\b"
+    }
+  }
+
+  variable "request.content" {
+    pattern {
+      value = "(?i)union\\s+select"
+    }
+  }
+
+  xml_payload {
+    namespace "apigee" {
+      value = "http://www.apigee.com"
+    }
+
+    xpath {
+      expression = "/apigee:Greeting/apigee:User"
+      type       = "string"
+
+      pattern {
+        value = "<\\s*script\\b"
+      }
+
+      pattern {
+        value = "(?i)union\\s+select"
+      }
+    }
+  }
+
+  json_payload {
+    json_path {
+      expression = "$.store.book[*].author"
+
+      pattern {
+        value = "<\\s*script\\b"
+      }
+    }
+  }
+}
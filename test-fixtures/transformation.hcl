@@ -0,0 +1,89 @@
+proxy "TransformationFixture" {}
+
+proxy_endpoint "default" {
+  http_proxy_connection {
+    base_path    = "/v0/transform"
+    virtual_host = ["default", "secure"]
+  }
+
+  pre_flow {
+    request {
+      step "json-to-xml" {
+        condition = "request.header.Content-Type = \"application/json\""
+      }
+    }
+  }
+
+  route_rule "default" {
+    target_endpoint = "default"
+  }
+}
+
+target_endpoint "default" {
+  http_target_connection {
+    url = "http://mocktarget.apigee.net"
+  }
+
+  post_flow {
+    response {
+      step "strip-envelope" {}
+      step "strip-namespaces" {}
+    }
+  }
+}
+
+policy json_to_xml "json-to-xml" {
+  display_name    = "JSON to XML"
+  source          = "request"
+  output_variable = "request"
+
+  options {
+    null_value                  = "NULL"
+    namespace_block_name        = "#namespaces"
+    default_namespace_node_name = "$default"
+    namespace_separator         = ":"
+    text_node_name              = "#text"
+    attribute_block_name        = "#attrs"
+    attribute_prefix            = "@"
+    invalid_chars_replacement   = "_"
+    object_root_element_name    = "Root"
+    array_root_element_name     = "Array"
+    array_item_element_name     = "Item"
+  }
+}
+
+policy xsl_transform "strip-envelope" {
+  display_name    = "Strip Envelope"
+  source          = "response"
+  resource_url    = "xsl://strip-envelope.xsl"
+  output_variable = "response.content"
+
+  parameters {
+    ignore_unresolved_variables = true
+
+    parameter "uid" {
+      ref = "request.header.uid"
+    }
+
+    parameter "mode" {
+      value = "strict"
+    }
+  }
+
+  content = <<EOF
+<xsl:stylesheet version="1.0" xmlns:xsl="http://www.w3.org/1999/XSL/Transform">
+  <xsl:param name="uid"/>
+  <xsl:param name="mode"/>
+  <xsl:template match="/">
+    <xsl:copy-of select="/*/*[local-name()='Body']/*"/>
+  </xsl:template>
+</xsl:stylesheet>
+EOF
+}
+
+policy xsl_transform "strip-namespaces" {
+  display_name    = "Strip Namespaces"
+  source          = "response"
+  output_variable = "response.content"
+  file            = "xsl/strip-namespaces.xsl"
+}
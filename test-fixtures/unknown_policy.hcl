@@ -0,0 +1,33 @@
+proxy "UnknownPolicyFixture" {}
+
+proxy_endpoint "default" {
+  pre_flow {
+    request {
+      step "add-cors" {}
+      step "check-quota" {}
+    }
+  }
+
+  http_proxy_connection {
+    base_path    = "/v0/hello"
+    virtual_host = ["default", "secure"]
+  }
+
+  route_rule "default" {
+    target_endpoint = "default"
+  }
+}
+
+target_endpoint "default" {
+  http_target_connection {
+    url = "http://mocktarget.apigee.net"
+  }
+}
+
+policy assign_mesage "add-cors" {
+  display_name = "Add CORS"
+}
+
+policy qouta "check-quota" {
+  display_name = "Check Quota"
+}
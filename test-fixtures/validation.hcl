@@ -0,0 +1,49 @@
+proxy "ValidationFixture" {}
+
+proxy_endpoint "default" {
+  pre_flow {
+    request {
+      step "check-quota" {}
+      step "missing-policy" {}
+    }
+  }
+
+  fault_rule "invalid_key" {
+    step "missing-fault-policy" {}
+  }
+
+  http_proxy_connection {
+    base_path    = "/v0/hello"
+    virtual_host = ["default", "secure"]
+  }
+
+  route_rule "default" {
+    target_endpoint = "missing-target"
+  }
+}
+
+proxy_endpoint "default" {}
+
+target_endpoint "unreachable" {
+  http_target_connection {
+    url = "http://mocktarget.apigee.net"
+  }
+}
+
+policy quota "check-quota" {
+  allow {
+    count = 5
+  }
+}
+
+policy quota "check-quota" {
+  allow {
+    count = 10
+  }
+}
+
+policy spike_arrest "unused-spike-arrest" {
+  rate {
+    value = "30ps"
+  }
+}
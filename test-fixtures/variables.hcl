@@ -0,0 +1,61 @@
+variable "base_path" {
+  default = "/v0/hello"
+}
+
+variable "target_host" {
+  description = "Host name of the backend service"
+}
+
+variable "quota_count" {
+  default = 100
+}
+
+variable "quota_enabled" {
+  default = true
+}
+
+locals {
+  target_url = "https://${local.api_host}/v1"
+  api_host   = "${var.target_host}"
+}
+
+proxy "Variables" {}
+
+proxy_endpoint "default" {
+  pre_flow {
+    request {
+      step "check-quota" {}
+    }
+  }
+
+  http_proxy_connection {
+    base_path    = "${var.base_path}"
+    virtual_host = ["default"]
+  }
+
+  route_rule "default" {
+    target_endpoint = "default"
+  }
+}
+
+target_endpoint "default" {
+  http_target_connection {
+    url = "${local.target_url}"
+  }
+}
+
+policy quota "check-quota" {
+  enabled = "${var.quota_enabled}"
+
+  allow {
+    count = "${var.quota_count}"
+  }
+
+  interval {
+    value = 1
+  }
+
+  time_unit {
+    value = "minute"
+  }
+}
@@ -0,0 +1,26 @@
+proxy "orders" {
+  display_name = "Orders"
+}
+
+proxy_endpoint "default" {
+  http_proxy_connection {
+    base_path    = "/v0/orders"
+    virtual_host = ["default"]
+  }
+
+  pre_flow {
+    request {
+      step "spike" {}
+    }
+  }
+
+  route_rule "default" {
+    target_endpoint = "default"
+  }
+}
+
+target_endpoint "default" {
+  http_target_connection {
+    url = "http://${var.target_host}"
+  }
+}
@@ -0,0 +1 @@
+context.setVariable("orders.source", "workspace");
@@ -0,0 +1,5 @@
+policy spike_arrest "spike" {
+  rate {
+    value = "10ps"
+  }
+}
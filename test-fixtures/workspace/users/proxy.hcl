@@ -0,0 +1,26 @@
+proxy "users" {
+  display_name = "Users"
+}
+
+proxy_endpoint "default" {
+  http_proxy_connection {
+    base_path    = "/v0/users"
+    virtual_host = ["default"]
+  }
+
+  pre_flow {
+    request {
+      step "spike" {}
+    }
+  }
+
+  route_rule "default" {
+    target_endpoint = "default"
+  }
+}
+
+target_endpoint "default" {
+  http_target_connection {
+    url = "http://${var.target_host}"
+  }
+}
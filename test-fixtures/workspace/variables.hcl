@@ -0,0 +1,3 @@
+variable "target_host" {
+  default = "mocktarget.apigee.net"
+}